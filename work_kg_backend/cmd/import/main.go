@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/config"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/importer"
+	"work_kg_backend/internal/moderation"
+	"work_kg_backend/internal/spreadsheet"
+)
+
+// Imports vacancies from a CSV or XLSX file:
+//
+//	go run ./cmd/import -file partners.xlsx -dry-run
+//
+// Like imports through the CRM, active jobs are then posted to the Telegram
+// channels.
+func main() {
+	path := flag.String("file", "", "CSV or XLSX file with vacancies")
+	dryRun := flag.Bool("dry-run", false, "only validate the file and print the preview")
+	skipInvalid := flag.Bool("skip-invalid", false, "import the valid rows even when others have errors")
+	flag.Parse()
+
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	format, err := spreadsheet.FormatFromName(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatal("Failed to open file:", err)
+	}
+	records, err := spreadsheet.ReadAll(file, format, importer.MaxRows+1)
+	file.Close()
+	if err != nil {
+		log.Fatal("Failed to read file:", err)
+	}
+
+	// Load configuration
+	cfg := config.Load()
+
+	// Connect to database
+	if err := database.Connect(cfg.DatabaseURL); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer database.Close()
+
+	// Initialize database schema
+	database.InitSchema()
+
+	// Imported jobs go through the same risk scoring as the CRM
+	moderation.Configure(cfg)
+
+	result, err := importer.Run(records, importer.Options{DryRun: *dryRun, SkipInvalid: *skipInvalid})
+	if result != nil {
+		for _, row := range result.Rows {
+			for _, rowErr := range row.Errors {
+				fmt.Printf("line %d: %s\n", row.Line, rowErr)
+			}
+		}
+		for _, column := range result.IgnoredColumns {
+			fmt.Printf("ignored column %q\n", column)
+		}
+		fmt.Printf("%d rows: %d valid, %d invalid, %d imported\n", result.Total, result.Valid, result.Invalid, result.Imported)
+	}
+
+	switch {
+	case errors.Is(err, importer.ErrInvalidRows):
+		log.Fatal("Nothing imported: fix the rows above or run with -skip-invalid")
+	case err != nil:
+		log.Fatal("Import failed:", err)
+	case *dryRun:
+		log.Println("Dry run, nothing imported")
+		return
+	}
+
+	if result.Imported > 0 {
+		if err := bot.Connect(cfg); err != nil {
+			log.Fatal("Imported, but failed to connect the bot to publish the jobs:", err)
+		}
+		bot.PublishImported(result)
+	}
+	log.Println("Import completed successfully!")
+}
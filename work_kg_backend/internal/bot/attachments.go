@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/storage"
+)
+
+const (
+	attachmentPhoto    = "photo"
+	attachmentDocument = "document"
+
+	skipAttachmentText = "Пропустить"
+
+	// maxDocumentSize is the largest file the Bot API lets us download
+	maxDocumentSize = 20 * 1024 * 1024
+	// maxCaptionLength is Telegram's limit for photo captions
+	maxCaptionLength = 1024
+)
+
+// attachmentFileID extracts the file of the expected kind from a message.
+// A "skip" answer returns skipAttachmentText.
+func attachmentFileID(message *tgbotapi.Message, kind string) (string, error) {
+	switch {
+	case kind == attachmentPhoto && len(message.Photo) > 0:
+		// Photo sizes are sorted ascending, keep the largest
+		return message.Photo[len(message.Photo)-1].FileID, nil
+	case kind == attachmentDocument && message.Document != nil:
+		if message.Document.MimeType != "application/pdf" {
+			return "", errors.New("Пожалуйста, отправьте резюме в формате PDF")
+		}
+		if message.Document.FileSize > maxDocumentSize {
+			return "", errors.New("Файл слишком большой: максимум 20 МБ")
+		}
+		return message.Document.FileID, nil
+	case message.Text == skipAttachmentText || message.Text == skipText:
+		return skipAttachmentText, nil
+	case kind == attachmentPhoto:
+		return "", errors.New("Отправьте фотографию или нажмите «Пропустить»")
+	default:
+		return "", errors.New("Отправьте PDF-файл или нажмите «Пропустить»")
+	}
+}
+
+// storeFile keeps a local copy of a Telegram file when storage is configured
+func storeFile(fileID string) {
+	if storage.Files == nil || fileID == "" {
+		return
+	}
+
+	body, err := downloadTelegramFile(fileID)
+	if err != nil {
+		log.Printf("Error downloading file %s: %v", fileID, err)
+		return
+	}
+	defer body.Close()
+
+	if err := storage.Files.Save(fileID, body); err != nil {
+		log.Printf("Error storing file %s: %v", fileID, err)
+	}
+}
+
+// OpenFile returns a file sent to the bot, from local storage when a copy
+// exists and from Telegram otherwise.
+func OpenFile(fileID string) (io.ReadCloser, error) {
+	if storage.Files != nil {
+		file, err := storage.Files.Open(fileID)
+		if err == nil {
+			return file, nil
+		}
+		if err != storage.ErrNotFound {
+			return nil, err
+		}
+	}
+
+	if Bot == nil {
+		return nil, storage.ErrNotFound
+	}
+	return downloadTelegramFile(fileID)
+}
+
+// downloadClient bounds Telegram file downloads, body included, so a stalled
+// transfer can't hold a goroutine forever
+var downloadClient = &http.Client{Timeout: 2 * time.Minute}
+
+func downloadTelegramFile(fileID string) (io.ReadCloser, error) {
+	fileURL, err := Bot.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, withoutURL(err)
+	}
+
+	resp, err := downloadClient.Get(fileURL)
+	if err != nil {
+		return nil, withoutURL(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("telegram returned %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// withoutURL drops the request URL from an HTTP error, since Bot API URLs
+// carry the bot token
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// sendJobCard sends a job card, with its photo when the job has one
+func sendJobCard(chatID int64, job models.Job, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	if job.PhotoFileID != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(job.PhotoFileID))
+		if utf8.RuneCountInString(text) <= maxCaptionLength {
+			photo.Caption = text
+			photo.ParseMode = "Markdown"
+			photo.ReplyMarkup = keyboard
+			Bot.Send(photo)
+			return
+		}
+		Bot.Send(photo)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
@@ -2,25 +2,52 @@ package bot
 
 import (
 	"log"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/config"
 	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/validation"
 )
 
 var Bot *tgbotapi.BotAPI
 var userStates = make(map[int64]*models.UserState)
+var appConfig *config.Config
+
+// Connect logs the bot in without listening for updates, which is enough to
+// publish jobs to the channels from other commands
+func Connect(cfg *config.Config) error {
+	appConfig = cfg
+	channels = cfg.Channels
+	reportThreshold = cfg.ReportThreshold
+	matchNotifyScore = cfg.MatchNotifyScore
 
-func Start(token string) {
 	var err error
-	Bot, err = tgbotapi.NewBotAPI(token)
+	Bot, err = tgbotapi.NewBotAPI(cfg.TelegramToken)
 	if err != nil {
-		log.Printf("Failed to create bot: %v", err)
-		return
+		return err
 	}
 
 	Bot.Debug = false
 	log.Printf("Authorized on account %s", Bot.Self.UserName)
+	if Bot.Self.UserName != cfg.BotUsername {
+		log.Printf("Warning: BOT_USERNAME is %s but the bot is %s, job links will open the wrong bot", cfg.BotUsername, Bot.Self.UserName)
+	}
+	return nil
+}
+
+func Start(cfg *config.Config) {
+	if err := Connect(cfg); err != nil {
+		log.Printf("Failed to create bot: %v", err)
+		return
+	}
+
+	if cfg.MatchNotifyInterval > 0 {
+		go runMatchNotifier(time.Duration(cfg.MatchNotifyInterval) * time.Minute)
+	}
+	go runReviewPrompter()
+	go runEventWriter()
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
@@ -28,6 +55,11 @@ func Start(token string) {
 	updates := Bot.GetUpdatesChan(u)
 
 	for update := range updates {
+		if update.InlineQuery != nil {
+			handleInlineQuery(update.InlineQuery)
+			continue
+		}
+
 		if update.CallbackQuery != nil {
 			handleCallback(update.CallbackQuery)
 			continue
@@ -51,11 +83,18 @@ func handleMessage(message *tgbotapi.Message) {
 	state := userStates[userID]
 
 	if message.IsCommand() {
+		trackEvent(userID, models.EventCommand, message.Command(), nil)
 		switch message.Command() {
 		case "start":
-			sendWelcome(chatID)
+			delete(userStates, userID)
+			if !handleStartPayload(chatID, message.CommandArguments()) {
+				sendWelcome(chatID)
+			}
 		case "menu":
+			delete(userStates, userID)
 			sendMainMenu(chatID)
+		case "cancel":
+			cancelWizard(chatID, userID)
 		case "help":
 			sendHelp(chatID)
 		default:
@@ -64,12 +103,28 @@ func handleMessage(message *tgbotapi.Message) {
 		return
 	}
 
+	if state != nil && state.State == "awaiting_location" {
+		handleLocationInput(chatID, message, state)
+		return
+	}
+
+	if state != nil && state.State == "awaiting_review_comment" {
+		handleReviewComment(chatID, userID, message, state)
+		return
+	}
+
 	// Handle state-based input
 	if state != nil {
 		handleStateInput(chatID, userID, message, state)
 		return
 	}
 
+	// A contact shared outside of a wizard still updates the profile phone
+	if message.Contact != nil && message.Contact.UserID == userID {
+		saveSharedPhone(chatID, userID, message.Contact.PhoneNumber)
+		return
+	}
+
 	sendMainMenu(chatID)
 }
 
@@ -80,3 +135,19 @@ func saveUser(from *tgbotapi.User) {
 	}
 	database.SaveUser(from.ID, username, from.FirstName, from.LastName, "")
 }
+
+func saveSharedPhone(chatID int64, userID int64, phone string) {
+	phone, err := validation.Phone(phone)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+		Bot.Send(msg)
+		return
+	}
+
+	database.SaveVerifiedPhone(userID, phone)
+
+	msg := tgbotapi.NewMessage(chatID, "✅ Номер телефона подтверждён: "+phone)
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	Bot.Send(msg)
+	sendMainMenu(chatID)
+}
@@ -1,12 +1,26 @@
 package bot
 
 import (
+	"slices"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/models"
 )
 
+// keepMessageCallbacks are actions on job cards that leave the card in place,
+// plus the review rating, whose prompt is removed only once it is accepted
+var keepMessageCallbacks = map[string]bool{
+	"apply":       true,
+	"save":        true,
+	"unsave":      true,
+	"report":      true,
+	"company":     true,
+	"review_rate": true,
+}
+
 func handleCallback(callback *tgbotapi.CallbackQuery) {
 	chatID := callback.Message.Chat.ID
 	userID := callback.From.ID
@@ -16,11 +30,15 @@ func handleCallback(callback *tgbotapi.CallbackQuery) {
 	// Answer callback
 	Bot.Request(tgbotapi.NewCallback(callback.ID, ""))
 
-	// Delete the message that contained the button (clean chat)
-	deleteMsg := tgbotapi.NewDeleteMessage(chatID, messageID)
-	Bot.Request(deleteMsg)
-
 	parts := strings.Split(data, ":")
+	trackEvent(userID, models.EventCallback, parts[0], map[string]interface{}{"data": data})
+
+	// Delete the message that contained the button (clean chat),
+	// except for job cards which the user may still want to read
+	if !keepMessageCallbacks[parts[0]] {
+		deleteMsg := tgbotapi.NewDeleteMessage(chatID, messageID)
+		Bot.Request(deleteMsg)
+	}
 
 	switch parts[0] {
 	case "menu":
@@ -83,6 +101,7 @@ func handleCallback(callback *tgbotapi.CallbackQuery) {
 				userStates[userID] = state
 			}
 			state.City = city
+			state.Location = nil
 
 			if searchType == "job" {
 				showJobs(chatID, state)
@@ -91,20 +110,27 @@ func handleCallback(callback *tgbotapi.CallbackQuery) {
 			}
 		}
 
+	case "city_location":
+		if len(parts) > 1 {
+			askLocation(chatID, userID, parts[1])
+		}
+
 	case "add_vacancy":
 		state := userStates[userID]
 		if state == nil {
 			state = &models.UserState{}
 			userStates[userID] = state
 		}
-		state.State = "awaiting_job_title"
 		state.TempJob = &models.Job{
 			Category:    state.Category,
 			Subcategory: state.Subcategory,
 			City:        state.City,
 		}
-		msg := tgbotapi.NewMessage(chatID, "Введите название вакансии:")
-		Bot.Send(msg)
+		if state.Location != nil {
+			state.TempJob.Latitude = &state.Location.Latitude
+			state.TempJob.Longitude = &state.Location.Longitude
+		}
+		startWizard(chatID, userID, state, "awaiting_job_title")
 
 	case "fill_form":
 		sendFormInstructions(chatID, userID)
@@ -114,9 +140,134 @@ func handleCallback(callback *tgbotapi.CallbackQuery) {
 			city := parts[1]
 			state := userStates[userID]
 			if state != nil && state.State == "form_city" {
-				state.FormCity = city
-				state.State = "form_specialty"
-				askFormQuestion(chatID, state, "Введите вашу специальность:")
+				advanceWizard(chatID, userID, state, city)
+			}
+		}
+
+	case "wizard_answer":
+		if len(parts) > 1 {
+			state := userStates[userID]
+			if state != nil {
+				// A button left over from an earlier step answers nothing here
+				if step, ok := wizardSteps[state.State]; ok && slices.Contains(step.Options, parts[1]) {
+					advanceWizard(chatID, userID, state, parts[1])
+				}
+			}
+		}
+
+	case "wizard_back":
+		state := userStates[userID]
+		if state == nil {
+			sendMainMenu(chatID)
+			return
+		}
+		wizardBack(chatID, userID, state)
+
+	case "wizard_cancel":
+		cancelWizard(chatID, userID)
+
+	case "apply":
+		if len(parts) > 1 {
+			jobID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err == nil {
+				applyToJob(chatID, callback.From, jobID)
+			}
+		}
+
+	case "recommended":
+		sendRecommendations(chatID, userID)
+
+	case "match_notify":
+		if len(parts) > 1 {
+			setMatchNotifications(chatID, userID, parts[1] == "on")
+		}
+
+	case "company":
+		if len(parts) > 1 {
+			companyID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err == nil {
+				sendCompanyProfile(chatID, companyID)
+			}
+		}
+
+	case "review_rate":
+		if len(parts) > 2 {
+			jobID, err := strconv.ParseInt(parts[1], 10, 64)
+			rating, ratingErr := strconv.Atoi(parts[2])
+			if err == nil && ratingErr == nil && rating >= 1 && rating <= 5 {
+				askReviewComment(chatID, userID, messageID, jobID, rating)
+			}
+		}
+
+	case "review_submit":
+		if state := userStates[userID]; state != nil && state.State == "awaiting_review_comment" {
+			submitReview(chatID, userID, state, "")
+		}
+
+	case "review_skip":
+		// The prompt is already removed
+
+	case "favorites":
+		sendFavorites(chatID, userID)
+
+	case "save", "unsave", "favorite_remove":
+		if len(parts) > 1 {
+			jobID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return
+			}
+			switch parts[0] {
+			case "save":
+				saveFavorite(chatID, userID, messageID, callback.Message.ReplyMarkup, jobID)
+			case "unsave":
+				unsaveFavorite(chatID, userID, messageID, callback.Message.ReplyMarkup, jobID)
+			default:
+				// The card itself was already deleted above
+				database.RemoveFavoriteJob(userID, jobID)
+			}
+		}
+
+	case "report":
+		if len(parts) > 1 {
+			jobID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err == nil {
+				sendReportReasons(chatID, jobID)
+			}
+		}
+
+	case "report_reason":
+		if len(parts) > 2 {
+			jobID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err == nil {
+				reportJob(chatID, userID, jobID, parts[2])
+			}
+		}
+
+	case "report_cancel":
+		// The reason picker was already deleted above
+
+	case "privacy":
+		sendPrivacySettings(chatID, userID)
+
+	case "privacy_visibility":
+		if len(parts) > 1 {
+			if _, ok := models.VisibilityLabels[parts[1]]; ok {
+				database.SetResumeVisibility(userID, parts[1])
+			}
+		}
+		sendPrivacySettings(chatID, userID)
+
+	case "privacy_anonymous":
+		if len(parts) > 1 {
+			database.SetResumeAnonymous(userID, parts[1] == "on")
+		}
+		sendPrivacySettings(chatID, userID)
+
+	case "contact_approve", "contact_decline":
+		if len(parts) > 1 {
+			requestID, err := strconv.ParseInt(parts[1], 10, 64)
+			if err == nil {
+				respondToContactRequest(chatID, userID, requestID, parts[0] == "contact_approve")
 			}
 		}
 
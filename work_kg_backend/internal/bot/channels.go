@@ -0,0 +1,188 @@
+package bot
+
+import (
+	"log"
+	"strconv"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/config"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+var channels []config.ChannelConfig
+
+// publishLocks serializes channel updates per job, so two quick edits can't
+// both see no post yet and post the job twice
+var publishLocks = struct {
+	sync.Mutex
+	jobs map[int64]*jobLock
+}{jobs: make(map[int64]*jobLock)}
+
+type jobLock struct {
+	sync.Mutex
+	users int
+}
+
+// lockJob waits until no other channel update of the job is running and
+// returns the function that releases it
+func lockJob(jobID int64) func() {
+	publishLocks.Lock()
+	lock, ok := publishLocks.jobs[jobID]
+	if !ok {
+		lock = &jobLock{}
+		publishLocks.jobs[jobID] = lock
+	}
+	lock.users++
+	publishLocks.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		publishLocks.Lock()
+		lock.users--
+		if lock.users == 0 {
+			delete(publishLocks.jobs, jobID)
+		}
+		publishLocks.Unlock()
+	}
+}
+
+func channelMatches(channel config.ChannelConfig, job models.Job) bool {
+	return (channel.City == "" || channel.City == job.City) &&
+		(channel.Category == "" || channel.Category == job.Category)
+}
+
+// PublishJob brings the job's channel posts in line with the job: active
+// jobs are posted to (or edited in) every matching channel, deactivated
+// jobs are marked as closed, and posts in channels the job no longer
+// matches are removed.
+func PublishJob(job models.Job) {
+	if Bot == nil || len(channels) == 0 {
+		return
+	}
+	defer lockJob(job.ID)()
+
+	posts, err := database.GetChannelPosts(job.ID)
+	if err != nil {
+		log.Printf("Error loading channel posts for job %d: %v", job.ID, err)
+		return
+	}
+	posted := make(map[string]models.ChannelPost)
+	for _, post := range posts {
+		posted[post.Channel] = post
+	}
+
+	for _, channel := range channels {
+		post, ok := posted[channel.Chat]
+		matches := channelMatches(channel, job)
+
+		switch {
+		case !ok && job.IsActive && matches:
+			sendChannelPost(channel.Chat, job)
+		case ok && !matches:
+			deleteChannelPost(post)
+		case ok && job.IsActive:
+			editChannelPost(post, channelPostText(job), channelPostKeyboard(job))
+		case ok:
+			editChannelPost(post, channelPostText(job)+"\n\n❌ *Вакансия закрыта*", nil)
+		}
+	}
+}
+
+// PublishImported posts the active jobs of an import to the channels
+func PublishImported(result *models.ImportResult) {
+	for _, row := range result.Rows {
+		if row.Job != nil && row.Job.IsActive && row.Job.ID != 0 {
+			PublishJob(*row.Job)
+		}
+	}
+}
+
+// RemoveJobPosts deletes the job's posts from all channels in the
+// background. It must be called before the job is purged, since posts
+// cascade with it.
+func RemoveJobPosts(jobID int64) {
+	if Bot == nil {
+		return
+	}
+
+	go func() {
+		defer lockJob(jobID)()
+
+		posts, err := database.GetChannelPosts(jobID)
+		if err != nil {
+			log.Printf("Error loading channel posts for job %d: %v", jobID, err)
+			return
+		}
+		for _, post := range posts {
+			deleteChannelPost(post)
+		}
+	}()
+}
+
+func channelPostText(job models.Job) string {
+	return formatJobCard(job)
+}
+
+func channelPostKeyboard(job models.Job) *tgbotapi.InlineKeyboardMarkup {
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("✉️ Откликнуться", jobDeepLink(job.ID)),
+		),
+	)
+	return &keyboard
+}
+
+func sendChannelPost(channel string, job models.Job) {
+	var msg tgbotapi.MessageConfig
+	if chatID, err := strconv.ParseInt(channel, 10, 64); err == nil {
+		msg = tgbotapi.NewMessage(chatID, channelPostText(job))
+	} else {
+		msg = tgbotapi.NewMessageToChannel(channel, channelPostText(job))
+	}
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = channelPostKeyboard(job)
+
+	sent, err := Bot.Send(msg)
+	if err != nil {
+		log.Printf("Error posting job %d to %s: %v", job.ID, channel, err)
+		return
+	}
+	database.SaveChannelPost(job.ID, channel, sent.MessageID)
+}
+
+func editChannelPost(post models.ChannelPost, text string, keyboard *tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.EditMessageTextConfig{
+		BaseEdit: channelEdit(post),
+		Text:     text,
+	}
+	edit.ParseMode = "Markdown"
+	edit.ReplyMarkup = keyboard
+
+	if _, err := Bot.Request(edit); err != nil {
+		log.Printf("Error editing post of job %d in %s: %v", post.JobID, post.Channel, err)
+	}
+}
+
+func deleteChannelPost(post models.ChannelPost) {
+	base := channelEdit(post)
+	deleteMsg := tgbotapi.DeleteMessageConfig{
+		ChatID:          base.ChatID,
+		ChannelUsername: base.ChannelUsername,
+		MessageID:       post.MessageID,
+	}
+	if _, err := Bot.Request(deleteMsg); err != nil {
+		log.Printf("Error deleting post of job %d in %s: %v", post.JobID, post.Channel, err)
+	}
+	database.DeleteChannelPost(post.JobID, post.Channel)
+}
+
+// channelEdit addresses a channel post by numeric chat ID or by @username
+func channelEdit(post models.ChannelPost) tgbotapi.BaseEdit {
+	if chatID, err := strconv.ParseInt(post.Channel, 10, 64); err == nil {
+		return tgbotapi.BaseEdit{ChatID: chatID, MessageID: post.MessageID}
+	}
+	return tgbotapi.BaseEdit{ChannelUsername: post.Channel, MessageID: post.MessageID}
+}
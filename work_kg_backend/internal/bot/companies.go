@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+)
+
+// companyJobsLimit caps how many vacancies the company card shows
+const companyJobsLimit = 10
+
+func sendCompanyProfile(chatID int64, companyID int64) {
+	company, err := database.GetCompanyByID(companyID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "😔 Компания не найдена.")
+		Bot.Send(msg)
+		return
+	}
+
+	text := fmt.Sprintf("🏢 *%s*\n", markdown(company.Name))
+	if company.Verified {
+		text += "✅ Проверенная компания\n"
+	}
+	if company.ReviewCount > 0 {
+		text += fmt.Sprintf("⭐ Рейтинг: %.1f (отзывов: %d)\n", company.Rating, company.ReviewCount)
+	}
+	if company.Description != "" {
+		text += fmt.Sprintf("\n%s\n", markdown(company.Description))
+	}
+	text += "\n"
+	if company.Phone != "" {
+		text += fmt.Sprintf("📞 Телефон: %s\n", markdown(company.Phone))
+	}
+	if company.Email != "" {
+		text += fmt.Sprintf("📧 Email: %s\n", markdown(company.Email))
+	}
+	if company.Website != "" {
+		text += fmt.Sprintf("🌐 Сайт: %s\n", markdown(company.Website))
+	}
+	text += fmt.Sprintf("📋 Открытых вакансий: %d", company.ActiveJobs)
+
+	if company.LogoURL != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(company.LogoURL))
+		Bot.Send(photo)
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	Bot.Send(msg)
+
+	jobs, err := database.GetCompanyJobs(companyID, true)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при загрузке вакансий компании")
+		Bot.Send(msg)
+		return
+	}
+	if len(jobs) > companyJobsLimit {
+		jobs = jobs[:companyJobsLimit]
+	}
+
+	saved := database.GetSavedJobIDs(chatID)
+	for _, job := range jobs {
+		sendJobCard(chatID, job, formatJobCard(job), jobCardKeyboard(job, saved[job.ID]))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск работы", "search_job"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+		),
+	)
+	msg = tgbotapi.NewMessage(chatID, fmt.Sprintf("Показано вакансий: %d", len(jobs)))
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
+
+// markdown escapes text typed by admins or employers for a Markdown message
+func markdown(text string) string {
+	return tgbotapi.EscapeText(tgbotapi.ModeMarkdown, text)
+}
@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+// wizardStarts maps each wizard to its first step
+var wizardStarts = map[string]string{
+	"job":  "awaiting_job_title",
+	"form": "form_name",
+}
+
+// WizardFunnel returns the ordered steps of the named wizard, following
+// Next from its first step, so optional steps like extra work history
+// entries are included
+func WizardFunnel(wizard string) ([]string, bool) {
+	first, ok := wizardStarts[wizard]
+	if !ok {
+		return nil, false
+	}
+
+	var steps []string
+	seen := make(map[string]bool)
+	for name := first; name != "" && !seen[name]; name = wizardSteps[name].Next {
+		seen[name] = true
+		steps = append(steps, name)
+	}
+	return steps, true
+}
+
+// wizardOf returns the wizard a state belongs to, or "" outside wizards
+func wizardOf(state string) string {
+	for wizard := range wizardStarts {
+		steps, _ := WizardFunnel(wizard)
+		if slices.Contains(steps, state) {
+			return wizard
+		}
+	}
+	return ""
+}
+
+// eventQueueSize is how many events can wait for the writer before new
+// ones are dropped
+const eventQueueSize = 1000
+
+// eventQueue feeds tracked events to runEventWriter
+var eventQueue = make(chan *models.BotEvent, eventQueueSize)
+
+// runEventWriter saves tracked events one at a time, so a burst of updates
+// can't open a database connection per event
+func runEventWriter() {
+	for event := range eventQueue {
+		database.SaveBotEvent(event)
+	}
+}
+
+// trackEvent records what a user did for the CRM analytics. It only queues
+// the event so the bot never waits on it; when the queue is full the event
+// is dropped.
+func trackEvent(telegramID int64, event, name string, properties map[string]interface{}) {
+	select {
+	case eventQueue <- &models.BotEvent{
+		TelegramID: telegramID,
+		Event:      event,
+		Name:       name,
+		Properties: properties,
+	}:
+	default:
+		log.Printf("Event queue full, dropping %s event", event)
+	}
+}
+
+// trackWizard records a wizard event for the given step
+func trackWizard(telegramID int64, event, step string) {
+	wizard := wizardOf(step)
+	if wizard == "" {
+		return
+	}
+	name := step
+	if event == models.EventWizardComplete {
+		name = wizard
+	}
+	trackEvent(telegramID, event, name, map[string]interface{}{"wizard": wizard, "step": step})
+}
+
+// trackSearch records a vacancy search and how many jobs it found
+func trackSearch(telegramID int64, kind string, state *models.UserState, query string, results int) {
+	properties := map[string]interface{}{"results": results}
+	if state != nil {
+		properties["category"] = state.Category
+		properties["subcategory"] = state.Subcategory
+		if state.Location == nil {
+			properties["city"] = state.City
+		}
+	}
+	if query = strings.ToLower(strings.TrimSpace(query)); query != "" {
+		properties["query"] = query
+	}
+	trackEvent(telegramID, models.EventSearch, kind, properties)
+}
@@ -0,0 +1,98 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+)
+
+func saveFavorite(chatID int64, userID int64, messageID int, markup *tgbotapi.InlineKeyboardMarkup, jobID int64) {
+	if database.SaveFavoriteJob(userID, jobID) != nil {
+		return
+	}
+	toggleFavoriteButton(chatID, messageID, markup, jobID, true)
+}
+
+func unsaveFavorite(chatID int64, userID int64, messageID int, markup *tgbotapi.InlineKeyboardMarkup, jobID int64) {
+	if database.RemoveFavoriteJob(userID, jobID) != nil {
+		return
+	}
+	toggleFavoriteButton(chatID, messageID, markup, jobID, false)
+}
+
+// toggleFavoriteButton swaps the save/unsave button on a job card in place
+func toggleFavoriteButton(chatID int64, messageID int, markup *tgbotapi.InlineKeyboardMarkup, jobID int64, saved bool) {
+	if markup == nil {
+		return
+	}
+
+	from, to := favoriteButton(jobID, !saved), favoriteButton(jobID, saved)
+	for _, row := range markup.InlineKeyboard {
+		for i, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == *from.CallbackData {
+				row[i] = to
+			}
+		}
+	}
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, *markup)
+	Bot.Request(edit)
+}
+
+func favoriteButton(jobID int64, saved bool) tgbotapi.InlineKeyboardButton {
+	if saved {
+		return tgbotapi.NewInlineKeyboardButtonData("✅ В избранном", fmt.Sprintf("unsave:%d", jobID))
+	}
+	return tgbotapi.NewInlineKeyboardButtonData("⭐ Сохранить", fmt.Sprintf("save:%d", jobID))
+}
+
+func sendFavorites(chatID int64, userID int64) {
+	jobs, err := database.GetSavedJobs(userID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при загрузке избранного")
+		Bot.Send(msg)
+		return
+	}
+
+	if len(jobs) == 0 {
+		text := "⭐ Избранное\n\nУ вас пока нет сохранённых вакансий. Нажмите «⭐ Сохранить» под вакансией, чтобы добавить её сюда."
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск работы", "search_job"),
+				tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+			),
+		)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		Bot.Send(msg)
+		return
+	}
+
+	for _, job := range jobs {
+		text := formatJobCard(job)
+		removeButton := tgbotapi.NewInlineKeyboardButtonData("🗑 Удалить", fmt.Sprintf("favorite_remove:%d", job.ID))
+
+		var keyboard tgbotapi.InlineKeyboardMarkup
+		if job.IsActive {
+			keyboard = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("✉️ Откликнуться", fmt.Sprintf("apply:%d", job.ID)),
+				removeButton,
+			))
+		} else {
+			text = "❌ *Вакансия закрыта*\n\n" + text
+			keyboard = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(removeButton))
+		}
+
+		sendJobCard(chatID, job, text, keyboard)
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+		),
+	)
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("⭐ В избранном %d вакансий", len(jobs)))
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+)
+
+// inlinePageSize is how many jobs are returned per inline query page
+const inlinePageSize = 20
+
+// handleInlineQuery answers "@work_kg_bot <query>" typed in any chat with
+// matching active jobs. Picking a result posts the job card with a button
+// leading back to the bot.
+func handleInlineQuery(query *tgbotapi.InlineQuery) {
+	offset, _ := strconv.Atoi(query.Offset)
+
+	jobs, err := database.SearchJobsByText(query.Query, inlinePageSize, offset)
+	if err != nil {
+		log.Printf("Error searching jobs for inline query: %v", err)
+		return
+	}
+	if offset == 0 && query.Query != "" {
+		trackSearch(query.From.ID, "inline", nil, query.Query, len(jobs))
+	}
+
+	results := make([]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		article := tgbotapi.NewInlineQueryResultArticleMarkdown(strconv.FormatInt(job.ID, 10), job.Title, formatJobCard(job))
+		article.Description = fmt.Sprintf("%s • %s", job.City, job.Subcategory)
+		if job.Salary != "" {
+			article.Description += " • " + job.Salary
+		}
+
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonURL("✉️ Откликнуться", jobDeepLink(job.ID)),
+			),
+		)
+		article.ReplyMarkup = &keyboard
+
+		results = append(results, article)
+	}
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     30,
+	}
+	if len(jobs) == inlinePageSize {
+		answer.NextOffset = strconv.Itoa(offset + inlinePageSize)
+	}
+	if len(jobs) == 0 && offset == 0 {
+		answer.SwitchPMText = "Вакансий не найдено — открыть бота"
+		answer.SwitchPMParameter = "inline"
+	}
+
+	if _, err := Bot.Request(answer); err != nil {
+		log.Printf("Error answering inline query: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+// jobCardKeyboard holds the actions shown under every job card
+func jobCardKeyboard(job models.Job, saved bool) tgbotapi.InlineKeyboardMarkup {
+	shareURL := "https://t.me/share/url?url=" + url.QueryEscape(jobDeepLink(job.ID))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✉️ Откликнуться", fmt.Sprintf("apply:%d", job.ID)),
+			favoriteButton(job.ID, saved),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL("🔗 Поделиться", shareURL),
+			tgbotapi.NewInlineKeyboardButtonData("⚠️ Пожаловаться", fmt.Sprintf("report:%d", job.ID)),
+		),
+	)
+	if job.CompanyID != nil {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏢 О компании", fmt.Sprintf("company:%d", *job.CompanyID)),
+		))
+	}
+	return keyboard
+}
+
+// handleStartPayload opens the screen requested by a t.me/<bot>?start=<payload> link.
+// It returns false when the payload is unknown.
+func handleStartPayload(chatID int64, payload string) bool {
+	if strings.HasPrefix(payload, "job_") {
+		jobID, err := strconv.ParseInt(strings.TrimPrefix(payload, "job_"), 10, 64)
+		if err != nil {
+			return false
+		}
+		sendJobDetails(chatID, jobID)
+		return true
+	}
+	if strings.HasPrefix(payload, "company_") {
+		companyID, err := strconv.ParseInt(strings.TrimPrefix(payload, "company_"), 10, 64)
+		if err != nil {
+			return false
+		}
+		sendCompanyProfile(chatID, companyID)
+		return true
+	}
+	return false
+}
+
+func sendJobDetails(chatID int64, jobID int64) {
+	job, err := database.GetJobByID(jobID)
+	if err != nil || !job.IsActive {
+		text := "😔 Вакансия не найдена или уже закрыта."
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск работы", "search_job"),
+				tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+			),
+		)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		Bot.Send(msg)
+		return
+	}
+
+	keyboard := jobCardKeyboard(*job, database.GetSavedJobIDs(chatID)[job.ID])
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+	))
+
+	sendJobCard(chatID, *job, formatJobCard(*job), keyboard)
+}
+
+// applyToJob records the application and gives the seeker the employer's
+// contact. The record keeps a second tap from counting twice and is what
+// the application statistics and employer review prompts build on.
+func applyToJob(chatID int64, from *tgbotapi.User, jobID int64) {
+	job, err := database.GetJobByID(jobID)
+	if err != nil || !job.IsActive {
+		msg := tgbotapi.NewMessage(chatID, "😔 Вакансия не найдена или уже закрыта.")
+		Bot.Send(msg)
+		return
+	}
+
+	isNew, err := database.SaveApplication(jobID, from.ID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при отправке отклика")
+		Bot.Send(msg)
+		return
+	}
+
+	text := fmt.Sprintf("✅ Вы откликнулись на вакансию «%s».\n\n📞 Свяжитесь с работодателем: %s", job.Title, job.Phone)
+	if !isNew {
+		text = fmt.Sprintf("Вы уже откликались на вакансию «%s».\n\n📞 Контакт работодателя: %s", job.Title, job.Phone)
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/models"
+)
+
+// nearbyRadiusKm limits location-based job search
+const nearbyRadiusKm = 50
+
+const (
+	locationShareText  = "📍 Отправить геолокацию"
+	locationCancelText = "⬅️ Выбрать город из списка"
+)
+
+func askLocation(chatID int64, userID int64, searchType string) {
+	state := userStates[userID]
+	if state == nil {
+		state = &models.UserState{}
+		userStates[userID] = state
+	}
+	state.SearchType = searchType
+	state.State = "awaiting_location"
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonLocation(locationShareText)),
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(locationCancelText)),
+	)
+	keyboard.OneTimeKeyboard = true
+
+	msg := tgbotapi.NewMessage(chatID, "Нажмите кнопку ниже, чтобы отправить свою геолокацию 👇")
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
+
+func handleLocationInput(chatID int64, message *tgbotapi.Message, state *models.UserState) {
+	if message.Location == nil {
+		if message.Text == locationCancelText {
+			state.State = ""
+			hideReplyKeyboard(chatID, "Хорошо, выберите город из списка")
+			sendCitySelection(chatID, state.SearchType)
+			return
+		}
+		msg := tgbotapi.NewMessage(chatID, "⚠️ Пожалуйста, отправьте геолокацию кнопкой ниже")
+		Bot.Send(msg)
+		return
+	}
+
+	point := models.GeoPoint{Latitude: message.Location.Latitude, Longitude: message.Location.Longitude}
+	city, distance := models.NearestCity(point)
+
+	state.State = ""
+	state.Location = &point
+	state.City = city
+
+	hideReplyKeyboard(chatID, fmt.Sprintf("📍 Ближайший город: %s (~%.0f км)", city, distance))
+
+	if state.SearchType == "employee" {
+		sendAddVacancyPrompt(chatID, state)
+		return
+	}
+	showJobs(chatID, state)
+}
+
+func hideReplyKeyboard(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	Bot.Send(msg)
+}
@@ -43,11 +43,15 @@ func sendMainMenu(chatID int64) {
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Личный кабинет 📁", "profile"),
+			tgbotapi.NewInlineKeyboardButtonData("Избранное ⭐", "favorites"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Поиск сотрудника 👷", "search_employee"),
 			tgbotapi.NewInlineKeyboardButtonData("Поиск работы 😌", "search_job"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Рекомендуемые вакансии 🎯", "recommended"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("Развлечение 😊", "entertainment"),
 			tgbotapi.NewInlineKeyboardButtonData("Зарабатывать вместе 💸", "earn_together"),
@@ -71,8 +75,11 @@ func sendHelp(chatID int64) {
 Команды бота:
 /start - Начать работу с ботом
 /menu - Главное меню
+/cancel - Отменить текущее действие
 /help - Помощь
 
+🔎 Поиск вакансий в любом чате: напишите @work_kg_bot и запрос, например «@work_kg_bot сварщик».
+
 По всем вопросам обращайтесь к администратору.`
 
 	msg := tgbotapi.NewMessage(chatID, text)
@@ -104,7 +111,11 @@ func sendProfile(chatID int64, userID int64) {
 		text += fmt.Sprintf("📱 Username: @%s\n", user.Username)
 	}
 	if user.Phone != "" {
-		text += fmt.Sprintf("📞 Телефон: %s\n", user.Phone)
+		if user.PhoneVerified {
+			text += fmt.Sprintf("📞 Телефон: %s ✅\n", user.Phone)
+		} else {
+			text += fmt.Sprintf("📞 Телефон: %s\n", user.Phone)
+		}
 	}
 	if user.City != "" {
 		text += fmt.Sprintf("📍 Город: %s\n", user.City)
@@ -115,16 +126,41 @@ func sendProfile(chatID int64, userID int64) {
 	if user.Experience != "" {
 		text += fmt.Sprintf("📝 Опыт: %s\n", user.Experience)
 	}
+	company, companyErr := database.GetCompanyByTelegramID(userID)
+	if companyErr == nil {
+		if company.Verified {
+			text += fmt.Sprintf("🏢 Компания: %s ✅\n", company.Name)
+		} else {
+			text += fmt.Sprintf("🏢 Компания: %s\n", company.Name)
+		}
+	}
+	resume, resumeErr := database.GetResumeByTelegramID(userID)
+	if resumeErr == nil && resume.CVFileID != "" {
+		text += "📎 Резюме (PDF): прикреплено\n"
+	}
 	text += fmt.Sprintf("📅 Дата регистрации: %s", user.CreatedAt.Format("02.01.2006"))
 
+	if resumeErr == nil && resume.PhotoFileID != "" {
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(resume.PhotoFileID))
+		Bot.Send(photo)
+	}
+
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📝 Редактировать анкету", "fill_form"),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "menu"),
+			tgbotapi.NewInlineKeyboardButtonData("🔒 Приватность", "privacy"),
 		),
 	)
+	if companyErr == nil {
+		keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🏢 Моя компания", fmt.Sprintf("company:%d", company.ID)),
+		))
+	}
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "menu"),
+	))
 
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ReplyMarkup = keyboard
@@ -132,16 +168,10 @@ func sendProfile(chatID int64, userID int64) {
 }
 
 func sendFormInstructions(chatID int64, userID int64) {
-	state := &models.UserState{State: "form_name", FormMessageIDs: []int{}}
+	state := &models.UserState{FormMessageIDs: []int{}}
 	userStates[userID] = state
 
-	text := "📝 Заполнение анкеты\n\nВведите ваше имя:"
-
-	msg := tgbotapi.NewMessage(chatID, text)
-	sentMsg, err := Bot.Send(msg)
-	if err == nil {
-		state.FormMessageIDs = append(state.FormMessageIDs, sentMsg.MessageID)
-	}
+	startWizard(chatID, userID, state, "form_name")
 }
 
 func showFormSummary(chatID int64, state *models.UserState) {
@@ -154,8 +184,32 @@ func showFormSummary(chatID int64, state *models.UserState) {
 📍 Город: %s
 💼 Специальность: %s
 📝 Опыт: %s
+`, state.FormName, state.FormPhone, state.FormCity, state.FormSpecialty, state.FormExperience)
 
-Работодатели смогут с вами связаться.`, state.FormName, state.FormPhone, state.FormCity, state.FormSpecialty, state.FormExperience)
+	if len(state.FormWorkHistory) > 0 {
+		text += "\n🏢 Места работы:\n"
+		for _, work := range state.FormWorkHistory {
+			text += fmt.Sprintf("• %s — %s (%s)\n", work.Employer, work.Position, work.Period)
+		}
+	}
+	if state.FormEducation != "" {
+		text += fmt.Sprintf("\n🎓 Образование: %s\n", state.FormEducation)
+	}
+	text += fmt.Sprintf("🛠 Навыки: %s\n", strings.Join(state.FormSkills, ", "))
+	if state.FormDesiredSalary != "" {
+		text += fmt.Sprintf("💰 Желаемая зарплата: %s\n", state.FormDesiredSalary)
+	}
+	if state.FormRelocate {
+		text += "🚚 Готов(а) к переезду\n"
+	}
+	text += fmt.Sprintf("🕒 График: %s\n", state.FormSchedule)
+	if state.FormPhotoFileID != "" {
+		text += "📷 Фото прикреплено\n"
+	}
+	if state.FormCVFileID != "" {
+		text += "📎 Резюме (PDF) прикреплено\n"
+	}
+	text += "\nРаботодатели смогут с вами связаться."
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
@@ -314,6 +368,10 @@ func sendCitySelection(chatID int64, searchType string) {
 		tgbotapi.NewInlineKeyboardButtonData("Чолпон-Ата 🇰🇬", fmt.Sprintf("city:Чолпон-Ата:%s", searchType)),
 	))
 
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("📍 По моей геолокации", fmt.Sprintf("city_location:%s", searchType)),
+	))
+
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "menu"),
 	))
@@ -325,13 +383,25 @@ func sendCitySelection(chatID int64, searchType string) {
 }
 
 func showJobs(chatID int64, state *models.UserState) {
-	jobs, err := database.SearchJobs(state.Category, state.Subcategory, state.City)
+	var jobs []models.Job
+	var err error
+	if state.Location != nil {
+		jobs, err = database.SearchJobsNear(state.Category, state.Subcategory, *state.Location, nearbyRadiusKm)
+	} else {
+		jobs, err = database.SearchJobs(state.Category, state.Subcategory, state.City)
+	}
 	if err != nil {
 		msg := tgbotapi.NewMessage(chatID, "Ошибка при поиске вакансий")
 		Bot.Send(msg)
 		return
 	}
 
+	kind := "category"
+	if state.Location != nil {
+		kind = "nearby"
+	}
+	trackSearch(chatID, kind, state, "", len(jobs))
+
 	if len(jobs) == 0 {
 		text := "😔 К сожалению, вакансий по вашему запросу не найдено.\n\nПопробуйте изменить параметры поиска."
 		keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -346,24 +416,9 @@ func showJobs(chatID int64, state *models.UserState) {
 		return
 	}
 
+	saved := database.GetSavedJobIDs(chatID)
 	for _, job := range jobs {
-		text := fmt.Sprintf("📋 *%s*\n\n", job.Title)
-		text += fmt.Sprintf("📍 Город: %s\n", job.City)
-		text += fmt.Sprintf("📂 Категория: %s / %s\n", job.Category, job.Subcategory)
-		if job.Salary != "" {
-			text += fmt.Sprintf("💰 Зарплата: %s\n", job.Salary)
-		}
-		if job.Company != "" {
-			text += fmt.Sprintf("🏢 Компания: %s\n", job.Company)
-		}
-		if job.Description != "" {
-			text += fmt.Sprintf("\n📝 %s\n", job.Description)
-		}
-		text += fmt.Sprintf("\n📞 Контакт: %s", job.Phone)
-
-		msg := tgbotapi.NewMessage(chatID, text)
-		msg.ParseMode = "Markdown"
-		Bot.Send(msg)
+		sendJobCard(chatID, job, formatJobCard(job), jobCardKeyboard(job, saved[job.ID]))
 	}
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
@@ -394,3 +449,34 @@ func sendAddVacancyPrompt(chatID int64, state *models.UserState) {
 	msg.ReplyMarkup = keyboard
 	Bot.Send(msg)
 }
+
+// formatJobCard renders a job as a Markdown message, escaping the fields users typed
+func formatJobCard(job models.Job) string {
+	text := fmt.Sprintf("📋 *%s*\n\n", markdown(job.Title))
+	text += fmt.Sprintf("📍 Город: %s\n", markdown(job.City))
+	if job.DistanceKm > 0 {
+		text += fmt.Sprintf("📏 ~%.0f км от вас\n", job.DistanceKm)
+	}
+	text += fmt.Sprintf("📂 Категория: %s / %s\n", markdown(job.Category), markdown(job.Subcategory))
+	if job.Salary != "" {
+		text += fmt.Sprintf("💰 Зарплата: %s\n", markdown(job.Salary))
+	}
+	if job.Company != "" && job.CompanyVerified {
+		text += fmt.Sprintf("🏢 Компания: %s ✅\n", markdown(job.Company))
+	} else if job.Company != "" {
+		text += fmt.Sprintf("🏢 Компания: %s\n", markdown(job.Company))
+	}
+	if job.EmployerReviews > 0 {
+		text += fmt.Sprintf("⭐ Рейтинг работодателя: %.1f (отзывов: %d)\n", job.EmployerRating, job.EmployerReviews)
+	}
+	if job.Description != "" {
+		text += fmt.Sprintf("\n📝 %s\n", markdown(job.Description))
+	}
+	text += fmt.Sprintf("\n📞 Контакт: %s", markdown(job.Phone))
+	return text
+}
+
+// jobDeepLink opens the bot on the given job
+func jobDeepLink(jobID int64) string {
+	return appConfig.JobLink(jobID)
+}
@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+func sendPrivacySettings(chatID int64, userID int64) {
+	resume, err := database.GetResumeByTelegramID(userID)
+	if err != nil {
+		text := "🔒 Приватность\n\nУ вас ещё нет анкеты. Заполните её, чтобы настроить видимость."
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("📝 Заполнить анкету", "fill_form"),
+			),
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "profile"),
+			),
+		)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		Bot.Send(msg)
+		return
+	}
+
+	anonymous := "выключен"
+	if resume.Anonymous {
+		anonymous = "включён"
+	}
+	text := fmt.Sprintf(`🔒 Приватность
+
+Видимость анкеты: %s
+Анонимный режим: %s
+
+🌐 Видна всем — работодатели видят имя и телефон.
+🔐 Контакты по запросу — телефон покажем только после вашего согласия.
+🙈 Скрыта — анкету никто не видит.
+
+В анонимном режиме имя, фото и телефон скрыты, пока вы не одобрите запрос работодателя.`,
+		models.VisibilityLabels[resume.Visibility], anonymous)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, visibility := range []string{models.VisibilityPublic, models.VisibilityOnRequest, models.VisibilityHidden} {
+		label := models.VisibilityLabels[visibility]
+		if visibility == resume.Visibility {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, "privacy_visibility:"+visibility),
+		))
+	}
+	if resume.Anonymous {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👤 Выключить анонимный режим", "privacy_anonymous:off"),
+		))
+	} else {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🕶 Включить анонимный режим", "privacy_anonymous:on"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "profile"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	Bot.Send(msg)
+}
+
+// SendContactRequest asks a job seeker whether an employer may see their contacts
+func SendContactRequest(request *models.ContactRequest, resume *models.Resume, requester string) {
+	if Bot == nil {
+		return
+	}
+
+	text := fmt.Sprintf("📨 %s запрашивает ваши контакты по анкете «%s».\n\nРазрешить показать ваше имя и телефон?",
+		requester, resume.Specialty)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Разрешить", fmt.Sprintf("contact_approve:%d", request.ID)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", fmt.Sprintf("contact_decline:%d", request.ID)),
+		),
+	)
+
+	msg := tgbotapi.NewMessage(resume.TelegramID, text)
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
+
+func respondToContactRequest(chatID int64, userID int64, requestID int64, approved bool) {
+	request, err := database.GetContactRequest(requestID)
+	if err != nil {
+		return
+	}
+	resume, err := database.GetResumeByID(request.ResumeID)
+	if err != nil || resume.TelegramID != userID {
+		return
+	}
+
+	updated, err := database.RespondToContactRequest(requestID, approved)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении ответа")
+		Bot.Send(msg)
+		return
+	}
+
+	text := "Вы уже ответили на этот запрос."
+	if updated && approved {
+		text = "✅ Контакты открыты для этого работодателя."
+	} else if updated {
+		text = "❌ Запрос отклонён. Ваши контакты остаются скрытыми."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+}
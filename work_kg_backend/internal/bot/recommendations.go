@@ -0,0 +1,148 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/matching"
+	"work_kg_backend/internal/models"
+)
+
+const (
+	// recommendationWindow limits recommendations to reasonably fresh jobs
+	recommendationWindow = 30 * 24 * time.Hour
+	recommendationLimit  = 10
+	// maxMatchNotifications caps how many jobs one user hears about per run
+	maxMatchNotifications = 3
+)
+
+var matchNotifyScore = 80
+
+func sendRecommendations(chatID int64, userID int64) {
+	resume, err := database.GetResumeByTelegramID(userID)
+	if err != nil {
+		text := "🎯 Рекомендуемые вакансии\n\nЗаполните анкету, и мы подберём вакансии по вашей специальности, городу и зарплате."
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("📝 Заполнить анкету", "fill_form"),
+				tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+			),
+		)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		Bot.Send(msg)
+		return
+	}
+
+	jobs, err := database.GetAllJobs(models.JobFilter{ActiveOnly: true, CreatedSince: time.Now().Add(-recommendationWindow)})
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при подборе вакансий")
+		Bot.Send(msg)
+		return
+	}
+
+	matches := matching.RecommendJobs(*resume, jobs, recommendationLimit)
+	if len(matches) == 0 {
+		text := "🎯 Рекомендуемые вакансии\n\nПока нет подходящих вакансий. Мы сообщим, когда появятся новые."
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск работы", "search_job"),
+				tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+			),
+		)
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = keyboard
+		Bot.Send(msg)
+		return
+	}
+
+	saved := database.GetSavedJobIDs(userID)
+	for _, match := range matches {
+		text := fmt.Sprintf("🎯 Совпадение: %d%%\n\n%s", match.Score, formatJobCard(match.Job))
+		sendJobCard(chatID, match.Job, text, jobCardKeyboard(match.Job, saved[match.Job.ID]))
+	}
+
+	notifyButton := tgbotapi.NewInlineKeyboardButtonData("🔕 Не присылать подборки", "match_notify:off")
+	if !resume.NotifyMatches {
+		notifyButton = tgbotapi.NewInlineKeyboardButtonData("🔔 Присылать подборки", "match_notify:on")
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(notifyButton),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔍 Поиск работы", "search_job"),
+			tgbotapi.NewInlineKeyboardButtonData("🏠 Главное меню", "menu"),
+		),
+	)
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🎯 Подобрано %d вакансий по вашей анкете", len(matches)))
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
+
+func setMatchNotifications(chatID int64, userID int64, enabled bool) {
+	if err := database.SetMatchNotifications(userID, enabled); err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении настройки")
+		Bot.Send(msg)
+		return
+	}
+
+	text := "🔕 Больше не будем присылать подборки вакансий."
+	if enabled {
+		text = "🔔 Будем присылать новые вакансии, которые подходят под вашу анкету."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+}
+
+// runMatchNotifier periodically tells job seekers about new jobs that
+// score high against their resume
+func runMatchNotifier(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		notifyMatches(time.Now().Add(-24 * time.Hour))
+	}
+}
+
+// notifyMatches checks jobs created since the given time. Jobs already sent
+// to a user are skipped, so overlapping windows don't repeat notifications.
+func notifyMatches(since time.Time) {
+	jobs, err := database.GetAllJobs(models.JobFilter{ActiveOnly: true, CreatedSince: since})
+	if err != nil {
+		log.Printf("Error loading jobs for matching: %v", err)
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+
+	resumes, err := database.GetAllResumes(models.ResumeFilter{})
+	if err != nil {
+		log.Printf("Error loading resumes for matching: %v", err)
+		return
+	}
+
+	for _, resume := range resumes {
+		if !resume.NotifyMatches {
+			continue
+		}
+
+		sent := 0
+		for _, match := range matching.RecommendJobs(resume, jobs, 0) {
+			if match.Score < matchNotifyScore || sent >= maxMatchNotifications {
+				break
+			}
+			isNew, err := database.SaveMatchNotification(match.Job.ID, resume.TelegramID, match.Score)
+			if err != nil || !isNew {
+				continue
+			}
+
+			text := fmt.Sprintf("🔔 Новая вакансия для вас — совпадение %d%%\n\n%s", match.Score, formatJobCard(match.Job))
+			sendJobCard(resume.TelegramID, match.Job, text, jobCardKeyboard(match.Job, false))
+			sent++
+		}
+	}
+}
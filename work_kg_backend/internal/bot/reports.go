@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+// reportThreshold is how many distinct pending reports deactivate a job
+var reportThreshold int
+
+func sendReportReasons(chatID int64, jobID int64) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, code := range models.ReportReasonOrder {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(models.ReportReasons[code], fmt.Sprintf("report_reason:%d:%s", jobID, code)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "report_cancel"),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, "⚠️ Что не так с этой вакансией?")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	Bot.Send(msg)
+}
+
+func reportJob(chatID int64, userID int64, jobID int64, reason string) {
+	if _, ok := models.ReportReasons[reason]; !ok {
+		return
+	}
+
+	isNew, err := database.SaveJobReport(jobID, userID, reason)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при отправке жалобы")
+		Bot.Send(msg)
+		return
+	}
+
+	text := "✅ Спасибо! Жалоба отправлена на проверку модератору."
+	if !isNew {
+		text = "Вы уже жаловались на эту вакансию. Модератор её проверит."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+
+	if isNew && reportThreshold > 0 && database.CountPendingReports(jobID) >= reportThreshold {
+		deactivateReportedJob(jobID)
+	}
+}
+
+// deactivateReportedJob hides a job that collected too many reports until a
+// moderator reviews it
+func deactivateReportedJob(jobID int64) {
+	deactivated, err := database.DeactivateReportedJob(jobID)
+	if err != nil {
+		log.Printf("Error deactivating reported job %d: %v", jobID, err)
+		return
+	}
+	if !deactivated {
+		return
+	}
+	log.Printf("Job %d deactivated after %d reports", jobID, reportThreshold)
+
+	if job, err := database.GetJobByID(jobID); err == nil {
+		go PublishJob(*job)
+	}
+}
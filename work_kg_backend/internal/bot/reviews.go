@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/reviews"
+	"work_kg_backend/internal/validation"
+)
+
+const (
+	reviewPromptInterval = time.Hour
+	// reviewPromptBatch caps how many review requests go out per run
+	reviewPromptBatch = 100
+)
+
+// runReviewPrompter asks job seekers to rate employers once the cooling
+// period after their application has passed
+func runReviewPrompter() {
+	ticker := time.NewTicker(reviewPromptInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		promptReviews()
+	}
+}
+
+func promptReviews() {
+	applications, err := database.GetApplicationsAwaitingReview(time.Now().Add(-reviews.Cooldown()), reviewPromptBatch)
+	if err != nil {
+		log.Printf("Error loading applications for reviews: %v", err)
+		return
+	}
+
+	for _, application := range applications {
+		job, err := database.GetJobByID(application.JobID)
+		if err != nil || job.CreatedBy == application.TelegramID ||
+			database.HasReviewedEmployer(application.TelegramID, job.CompanyID, job.CreatedBy) {
+			// Nothing to ask about; don't pick the application up again
+			database.MarkReviewRequested(application.ID)
+			continue
+		}
+
+		employer := "работодателя"
+		if job.Company != "" {
+			employer = fmt.Sprintf("работодателя «%s»", job.Company)
+		}
+		text := fmt.Sprintf("Вы откликались на вакансию «%s».\n\nКак вам работа с %s? Оцените от 1 до 5 — это поможет другим соискателям.",
+			job.Title, employer)
+
+		var ratingRow []tgbotapi.InlineKeyboardButton
+		for rating := 1; rating <= 5; rating++ {
+			ratingRow = append(ratingRow, tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("%d ⭐", rating), fmt.Sprintf("review_rate:%d:%d", job.ID, rating)))
+		}
+		keyboard := tgbotapi.NewInlineKeyboardMarkup(
+			ratingRow,
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData("Пропустить", "review_skip"),
+			),
+		)
+
+		msg := tgbotapi.NewMessage(application.TelegramID, text)
+		msg.ReplyMarkup = keyboard
+		if _, err := Bot.Send(msg); err != nil {
+			log.Printf("Error asking %d for a review: %v", application.TelegramID, err)
+			// Try again next run, unless the user blocked the bot
+			var apiErr *tgbotapi.Error
+			if !errors.As(err, &apiErr) || apiErr.Code != 403 {
+				continue
+			}
+		}
+		database.MarkReviewRequested(application.ID)
+	}
+}
+
+// askReviewComment starts the review comment unless the user is in the middle
+// of something else, in which case the rating prompt stays for later
+func askReviewComment(chatID int64, userID int64, messageID int, jobID int64, rating int) {
+	if state := userStates[userID]; state != nil && state.State != "awaiting_review_comment" {
+		msg := tgbotapi.NewMessage(chatID, "Сначала завершите текущее действие или отмените его командой /cancel, затем поставьте оценку.")
+		Bot.Send(msg)
+		return
+	}
+	Bot.Request(tgbotapi.NewDeleteMessage(chatID, messageID))
+
+	userStates[userID] = &models.UserState{
+		State:        "awaiting_review_comment",
+		ReviewJobID:  jobID,
+		ReviewRating: rating,
+	}
+
+	text := fmt.Sprintf("Ваша оценка: %d ⭐\n\nНапишите короткий отзыв о работодателе или отправьте оценку без текста.", rating)
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Отправить без отзыва", "review_submit"),
+		),
+	)
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	Bot.Send(msg)
+}
+
+func handleReviewComment(chatID int64, userID int64, message *tgbotapi.Message, state *models.UserState) {
+	comment, err := validation.Text(message.Text, 3, validation.MaxReviewLength)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+		Bot.Send(msg)
+		return
+	}
+	submitReview(chatID, userID, state, comment)
+}
+
+func submitReview(chatID int64, userID int64, state *models.UserState, comment string) {
+	delete(userStates, userID)
+
+	job, err := database.GetJobByID(state.ReviewJobID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, "😔 Вакансия не найдена.")
+		Bot.Send(msg)
+		return
+	}
+
+	review := &models.EmployerReview{
+		TelegramID: userID,
+		Rating:     state.ReviewRating,
+		Comment:    comment,
+	}
+	if err := reviews.Submit(review, job); err != nil {
+		msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+		Bot.Send(msg)
+		return
+	}
+
+	text := "✅ Спасибо за оценку!"
+	if review.Status == models.ReviewPending {
+		text = "✅ Спасибо! Отзыв появится после проверки модератором."
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+}
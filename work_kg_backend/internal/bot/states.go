@@ -1,111 +1,689 @@
 package bot
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/moderation"
+	"work_kg_backend/internal/validation"
 )
 
+// wizardStep is a single input step of a bot wizard. Steps are linked by
+// state name: Prev is where "⬅️ Назад" leads (empty on the first step) and
+// Next is where the wizard advances after Apply (empty on the last step,
+// which runs Finish instead). Validate checks and normalizes the answer
+// before Apply; on error the step is asked again. Finish reports whether
+// the result was saved. Route, when set, picks the
+// next state from the answer, and Undo clears what Apply stored when the
+// user steps back past this step.
+type wizardStep struct {
+	Prompt string
+	Prev   string
+	Next   string
+	// Form steps belong to the resume form: every message is collected
+	// and deleted once the form is finished or cancelled.
+	Form bool
+	// RequestContact steps offer a "share my phone" reply keyboard; typing
+	// the number by hand still works.
+	RequestContact bool
+	// Options are offered as inline buttons; typed answers must match one
+	Options []string
+	// Attachment steps expect a photo or document instead of text; the
+	// answer passed to Apply is its file_id or skipAttachmentText
+	Attachment string
+	Ask        func(chatID int64, state *models.UserState, step wizardStep)
+	Validate   func(text string) (string, error)
+	Apply      func(state *models.UserState, text string)
+	Route      func(state *models.UserState, text string) string
+	Undo       func(state *models.UserState)
+	Finish     func(chatID int64, userID int64, state *models.UserState) bool
+}
+
+// textLength returns a validator for plain text of the given length.
+func textLength(minLen, maxLen int) func(string) (string, error) {
+	return func(text string) (string, error) {
+		return validation.Text(text, minLen, maxLen)
+	}
+}
+
+// optionalText is like textLength but lets the user skip the step with "-".
+func optionalText(maxLen int) func(string) (string, error) {
+	return func(text string) (string, error) {
+		if strings.TrimSpace(text) == "-" {
+			return "-", nil
+		}
+		return validation.Text(text, 1, maxLen)
+	}
+}
+
+// oneOf accepts only the given options
+func oneOf(options ...string) func(string) (string, error) {
+	return func(text string) (string, error) {
+		text = strings.TrimSpace(text)
+		for _, option := range options {
+			if strings.EqualFold(text, option) {
+				return option, nil
+			}
+		}
+		return "", errors.New("Пожалуйста, выберите один из вариантов кнопками")
+	}
+}
+
+const (
+	workMoreText = "➕ Добавить ещё"
+	workDoneText = "➡️ Далее"
+	skipText     = "-"
+)
+
+// maxSkills limits how many skill tags a resume can have
+const maxSkills = 20
+
+// currentWork returns the work history entry being filled in, starting a
+// new one when the last entry is already complete
+func currentWork(state *models.UserState) *models.WorkExperience {
+	n := len(state.FormWorkHistory)
+	if n == 0 || state.FormWorkHistory[n-1].Period != "" {
+		state.FormWorkHistory = append(state.FormWorkHistory, models.WorkExperience{})
+		n++
+	}
+	return &state.FormWorkHistory[n-1]
+}
+
+// dropIncompleteWork removes a work history entry the user abandoned midway
+func dropIncompleteWork(state *models.UserState) {
+	n := len(state.FormWorkHistory)
+	if n > 0 && state.FormWorkHistory[n-1].Period == "" {
+		state.FormWorkHistory = state.FormWorkHistory[:n-1]
+	}
+}
+
+// reopenWork marks the last work history entry as being filled in again, so
+// the next answer edits it instead of adding a new one
+func reopenWork(state *models.UserState) {
+	if n := len(state.FormWorkHistory); n > 0 {
+		state.FormWorkHistory[n-1].Period = ""
+	}
+}
+
+// parseSkills splits a comma separated list into unique skill tags
+func parseSkills(text string) (string, error) {
+	var skills []string
+	seen := make(map[string]bool)
+	for _, skill := range strings.Split(text, ",") {
+		skill = strings.TrimSpace(skill)
+		if skill == "" || seen[strings.ToLower(skill)] {
+			continue
+		}
+		if len([]rune(skill)) > 50 {
+			return "", fmt.Errorf("Навык «%s» слишком длинный", skill)
+		}
+		seen[strings.ToLower(skill)] = true
+		skills = append(skills, skill)
+	}
+	if len(skills) == 0 {
+		return "", validation.ErrEmpty
+	}
+	if len(skills) > maxSkills {
+		return "", fmt.Errorf("Укажите не больше %d навыков", maxSkills)
+	}
+	return strings.Join(skills, ", "), nil
+}
+
+var wizardSteps = map[string]wizardStep{
+	// Vacancy wizard
+	"awaiting_job_title": {
+		Prompt:   "Введите название вакансии:",
+		Next:     "awaiting_job_description",
+		Validate: textLength(3, validation.MaxTitleLength),
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.Title = text
+		},
+	},
+	"awaiting_job_description": {
+		Prompt:   "Введите описание вакансии:",
+		Prev:     "awaiting_job_title",
+		Next:     "awaiting_job_salary",
+		Validate: textLength(1, validation.MaxDescriptionLength),
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.Description = text
+		},
+	},
+	"awaiting_job_salary": {
+		Prompt:   "Введите зарплату (например: 30000-50000 сом):",
+		Prev:     "awaiting_job_description",
+		Next:     "awaiting_job_phone",
+		Validate: validation.Salary,
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.Salary = text
+		},
+	},
+	"awaiting_job_phone": {
+		Prompt:         "Введите контактный телефон:",
+		Prev:           "awaiting_job_salary",
+		Next:           "awaiting_job_company",
+		RequestContact: true,
+		Validate:       validation.Phone,
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.Phone = text
+		},
+	},
+	"awaiting_job_company": {
+		Prompt:   "Введите название компании (или '-' если нет):",
+		Prev:     "awaiting_job_phone",
+		Next:     "awaiting_job_photo",
+		Validate: optionalText(validation.MaxCompanyLength),
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.Company = ""
+			if text != "-" {
+				state.TempJob.Company = text
+			}
+		},
+	},
+	"awaiting_job_photo": {
+		Prompt:     "📷 Прикрепите фото к вакансии (объект, рабочее место) или нажмите «Пропустить»:",
+		Prev:       "awaiting_job_company",
+		Attachment: attachmentPhoto,
+		Options:    []string{skipAttachmentText},
+		Apply: func(state *models.UserState, text string) {
+			state.TempJob.PhotoFileID = ""
+			if text != skipAttachmentText {
+				state.TempJob.PhotoFileID = text
+			}
+		},
+		Finish: finishJobWizard,
+	},
+
+	// Step-by-step resume form
+	"form_name": {
+		Prompt:   "📝 Заполнение анкеты\n\nВведите ваше имя:",
+		Next:     "form_phone",
+		Form:     true,
+		Validate: textLength(2, validation.MaxNameLength),
+		Apply: func(state *models.UserState, text string) {
+			state.FormName = text
+		},
+	},
+	"form_phone": {
+		Prompt:         "Введите ваш номер телефона (+996 XXX XXX XXX):",
+		Prev:           "form_name",
+		Next:           "form_city",
+		Form:           true,
+		RequestContact: true,
+		Validate:       validation.Phone,
+		Apply: func(state *models.UserState, text string) {
+			state.FormPhone = text
+		},
+	},
+	"form_city": {
+		Prompt:   "Выберите ваш город или введите свой:",
+		Prev:     "form_phone",
+		Next:     "form_specialty",
+		Form:     true,
+		Ask:      askFormCityQuestion,
+		Validate: textLength(2, validation.MaxCityLength),
+		Apply: func(state *models.UserState, text string) {
+			state.FormCity = text
+		},
+	},
+	"form_specialty": {
+		Prompt:   "Введите вашу специальность:",
+		Prev:     "form_city",
+		Next:     "form_experience",
+		Form:     true,
+		Validate: textLength(2, validation.MaxSpecialtyLength),
+		Apply: func(state *models.UserState, text string) {
+			state.FormSpecialty = text
+		},
+	},
+	"form_experience": {
+		Prompt:   "Кратко опишите ваш опыт работы:",
+		Prev:     "form_specialty",
+		Next:     "form_work_employer",
+		Form:     true,
+		Validate: textLength(1, validation.MaxExperienceLength),
+		Apply: func(state *models.UserState, text string) {
+			state.FormExperience = text
+		},
+	},
+	"form_work_employer": {
+		Prompt:   "🏢 Места работы\n\nГде вы работали? Введите название организации (или '-' чтобы пропустить):",
+		Prev:     "form_experience",
+		Next:     "form_work_position",
+		Form:     true,
+		Validate: optionalText(validation.MaxCompanyLength),
+		Apply: func(state *models.UserState, text string) {
+			if text == skipText {
+				dropIncompleteWork(state)
+				return
+			}
+			currentWork(state).Employer = text
+		},
+		Route: func(state *models.UserState, text string) string {
+			if text == skipText {
+				return "form_education"
+			}
+			return "form_work_position"
+		},
+		Undo: dropIncompleteWork,
+	},
+	"form_work_position": {
+		Prompt:   "Кем вы работали? Введите должность:",
+		Prev:     "form_work_employer",
+		Next:     "form_work_period",
+		Form:     true,
+		Validate: textLength(2, validation.MaxSpecialtyLength),
+		Apply: func(state *models.UserState, text string) {
+			currentWork(state).Position = text
+		},
+	},
+	"form_work_period": {
+		Prompt:   "Период работы (например: 2020–2023 или 2 года):",
+		Prev:     "form_work_position",
+		Next:     "form_work_more",
+		Form:     true,
+		Validate: textLength(2, 100),
+		Apply: func(state *models.UserState, text string) {
+			currentWork(state).Period = text
+		},
+		Undo: reopenWork,
+	},
+	"form_work_more": {
+		Prompt:   "Добавить ещё одно место работы?",
+		Prev:     "form_work_period",
+		Next:     "form_education",
+		Form:     true,
+		Options:  []string{workMoreText, workDoneText},
+		Validate: oneOf(workMoreText, workDoneText),
+		Apply:    func(state *models.UserState, text string) {},
+		Route: func(state *models.UserState, text string) string {
+			if text == workMoreText {
+				return "form_work_employer"
+			}
+			return "form_education"
+		},
+		// Stepping back asks for the period again, which must edit the last
+		// entry rather than start a new one
+		Undo: reopenWork,
+	},
+	"form_education": {
+		Prompt:   "🎓 Укажите ваше образование (учебное заведение, специальность, год) или '-' если нет:",
+		Prev:     "form_work_employer",
+		Next:     "form_skills",
+		Form:     true,
+		Validate: optionalText(500),
+		Apply: func(state *models.UserState, text string) {
+			state.FormEducation = ""
+			if text != skipText {
+				state.FormEducation = text
+			}
+		},
+	},
+	"form_skills": {
+		Prompt:   "🛠 Перечислите ваши навыки через запятую (например: сварка, чтение чертежей, водительские права B):",
+		Prev:     "form_education",
+		Next:     "form_salary",
+		Form:     true,
+		Validate: parseSkills,
+		Apply: func(state *models.UserState, text string) {
+			state.FormSkills = strings.Split(text, ", ")
+		},
+	},
+	"form_salary": {
+		Prompt: "💰 Желаемая зарплата (например: от 40000 сом) или '-' если не важно:",
+		Prev:   "form_skills",
+		Next:   "form_relocate",
+		Form:   true,
+		Validate: func(text string) (string, error) {
+			if strings.TrimSpace(text) == skipText {
+				return skipText, nil
+			}
+			return validation.Salary(text)
+		},
+		Apply: func(state *models.UserState, text string) {
+			state.FormDesiredSalary = ""
+			if text != skipText {
+				state.FormDesiredSalary = text
+			}
+		},
+	},
+	"form_relocate": {
+		Prompt:   "🚚 Готовы к переезду в другой город?",
+		Prev:     "form_salary",
+		Next:     "form_schedule",
+		Form:     true,
+		Options:  []string{"Да", "Нет"},
+		Validate: oneOf("Да", "Нет"),
+		Apply: func(state *models.UserState, text string) {
+			state.FormRelocate = text == "Да"
+		},
+	},
+	"form_schedule": {
+		Prompt:   "🕒 Какой график работы вам подходит?",
+		Prev:     "form_relocate",
+		Form:     true,
+		Options:  models.Schedules,
+		Validate: oneOf(models.Schedules...),
+		Next:     "form_photo",
+		Apply: func(state *models.UserState, text string) {
+			state.FormSchedule = text
+		},
+	},
+	"form_photo": {
+		Prompt:     "📷 Отправьте своё фото для анкеты или нажмите «Пропустить»:",
+		Prev:       "form_schedule",
+		Next:       "form_cv",
+		Form:       true,
+		Attachment: attachmentPhoto,
+		Options:    []string{skipAttachmentText},
+		Apply: func(state *models.UserState, text string) {
+			state.FormPhotoFileID = ""
+			if text != skipAttachmentText {
+				state.FormPhotoFileID = text
+			}
+		},
+	},
+	"form_cv": {
+		Prompt:     "📎 Если у вас есть резюме в PDF, отправьте его файлом или нажмите «Пропустить»:",
+		Prev:       "form_photo",
+		Form:       true,
+		Attachment: attachmentDocument,
+		Options:    []string{skipAttachmentText},
+		Apply: func(state *models.UserState, text string) {
+			state.FormCVFileID = ""
+			if text != skipAttachmentText {
+				state.FormCVFileID = text
+			}
+		},
+		Finish: finishFormWizard,
+	},
+}
+
 func handleStateInput(chatID int64, userID int64, message *tgbotapi.Message, state *models.UserState) {
+	step, ok := wizardSteps[state.State]
+	if !ok {
+		return
+	}
+
+	if step.Form {
+		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
+	}
+
 	text := message.Text
+	if step.Attachment != "" {
+		fileID, err := attachmentFileID(message, step.Attachment)
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+			sendStepMessage(state, step, msg)
+			askStep(chatID, state)
+			return
+		}
+		text = fileID
+	}
 
-	switch state.State {
-	case "awaiting_job_title":
-		if state.TempJob == nil {
-			state.TempJob = &models.Job{}
+	if step.RequestContact {
+		// Navigation buttons live on the reply keyboard for these steps
+		switch text {
+		case contactBackText:
+			hideContactKeyboard(chatID, state, step, contactBackText)
+			wizardBack(chatID, userID, state)
+			return
+		case contactCancelText:
+			cancelWizard(chatID, userID)
+			return
 		}
-		state.TempJob.Title = text
-		state.State = "awaiting_job_description"
-		msg := tgbotapi.NewMessage(chatID, "Введите описание вакансии:")
-		Bot.Send(msg)
 
-	case "awaiting_job_description":
-		state.TempJob.Description = text
-		state.State = "awaiting_job_salary"
-		msg := tgbotapi.NewMessage(chatID, "Введите зарплату (например: 30000-50000 сом):")
-		Bot.Send(msg)
+		state.PhoneVerified = false
+		if message.Contact != nil {
+			text = message.Contact.PhoneNumber
+			state.PhoneVerified = message.Contact.UserID == userID
+		}
+	}
 
-	case "awaiting_job_salary":
-		state.TempJob.Salary = text
-		state.State = "awaiting_job_phone"
-		msg := tgbotapi.NewMessage(chatID, "Введите контактный телефон:")
-		Bot.Send(msg)
+	advanceWizard(chatID, userID, state, text)
+}
 
-	case "awaiting_job_phone":
-		state.TempJob.Phone = text
-		state.State = "awaiting_job_company"
-		msg := tgbotapi.NewMessage(chatID, "Введите название компании (или '-' если нет):")
-		Bot.Send(msg)
+// startWizard puts the user into the given wizard step and asks its question.
+func startWizard(chatID int64, userID int64, state *models.UserState, stateName string) {
+	state.State = stateName
+	trackWizard(userID, models.EventWizardStep, stateName)
+	askStep(chatID, state)
+}
+
+// advanceWizard applies the answer to the current step and moves on to the
+// next one, finishing the wizard after its last step.
+func advanceWizard(chatID int64, userID int64, state *models.UserState, text string) {
+	step := wizardSteps[state.State]
+
+	if step.Validate != nil {
+		normalized, err := step.Validate(text)
+		if err != nil {
+			trackWizard(userID, models.EventWizardError, state.State)
+			msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+			sendStepMessage(state, step, msg)
+			askStep(chatID, state)
+			return
+		}
+		text = normalized
+	}
 
-	case "awaiting_job_company":
-		if text != "-" {
-			state.TempJob.Company = text
+	if step.RequestContact {
+		if state.PhoneVerified {
+			database.SaveVerifiedPhone(userID, text)
 		}
-		// Save job
-		state.TempJob.CreatedBy = userID
-		state.TempJob.Source = "telegram"
-		database.SaveJob(state.TempJob)
-		delete(userStates, userID)
+		hideContactKeyboard(chatID, state, step, "📞 "+text)
+	}
 
-		msg := tgbotapi.NewMessage(chatID, "✅ Вакансия успешно добавлена!")
-		Bot.Send(msg)
+	step.Apply(state, text)
+
+	next := step.Next
+	if step.Route != nil {
+		next = step.Route(state, text)
+	}
+
+	if next == "" {
+		last := state.State
+		if step.Finish(chatID, userID, state) {
+			trackWizard(userID, models.EventWizardComplete, last)
+		}
+		return
+	}
+
+	state.State = next
+	trackWizard(userID, models.EventWizardStep, next)
+	askStep(chatID, state)
+}
+
+// wizardBack returns the user to the previous step, or cancels the wizard
+// when they are already on the first one.
+func wizardBack(chatID int64, userID int64, state *models.UserState) {
+	step, ok := wizardSteps[state.State]
+	if !ok {
 		sendMainMenu(chatID)
+		return
+	}
 
-	// Step-by-step form handling
-	case "form_name":
-		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
-		state.FormName = text
-		state.State = "form_phone"
-		askFormQuestion(chatID, state, "Введите ваш номер телефона (+996 XXX XXX XXX):")
+	if step.Prev == "" {
+		cancelWizard(chatID, userID)
+		return
+	}
 
-	case "form_phone":
-		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
-		state.FormPhone = text
-		state.State = "form_city"
-		askFormCityQuestion(chatID, state)
+	if step.Undo != nil {
+		step.Undo(state)
+	}
+	state.State = step.Prev
+	askStep(chatID, state)
+}
 
-	case "form_city":
-		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
-		state.FormCity = text
-		state.State = "form_specialty"
-		askFormQuestion(chatID, state, "Введите вашу специальность:")
+// cancelWizard drops whatever the user was filling in and returns to the menu.
+func cancelWizard(chatID int64, userID int64) {
+	state := userStates[userID]
+	delete(userStates, userID)
 
-	case "form_specialty":
-		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
-		state.FormSpecialty = text
-		state.State = "form_experience"
-		askFormQuestion(chatID, state, "Опишите ваш опыт работы:")
+	if state != nil {
+		if step, ok := wizardSteps[state.State]; ok {
+			trackWizard(userID, models.EventWizardCancel, state.State)
+			if step.Form {
+				deleteAllFormMessages(chatID, state)
+			}
+			hideReplyKeyboard(chatID, "❌ Действие отменено")
+		} else if state.State == "awaiting_location" {
+			// The location request left its reply keyboard open
+			hideReplyKeyboard(chatID, "❌ Действие отменено")
+		}
+	}
 
-	case "form_experience":
-		state.FormMessageIDs = append(state.FormMessageIDs, message.MessageID)
-		state.FormExperience = text
-		// Delete ALL collected messages at the end
-		deleteAllFormMessages(chatID, state)
-		// Save form data and show confirmation
-		saveFormData(userID, state)
-		showFormSummary(chatID, state)
-		delete(userStates, userID)
+	sendMainMenu(chatID)
+}
+
+func askStep(chatID int64, state *models.UserState) {
+	step := wizardSteps[state.State]
+	if step.Ask != nil {
+		step.Ask(chatID, state, step)
+		return
 	}
+
+	msg := tgbotapi.NewMessage(chatID, step.Prompt)
+	if step.RequestContact {
+		msg.ReplyMarkup = contactKeyboard(step)
+	} else {
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for i := 0; i < len(step.Options); i += 2 {
+			row := tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(step.Options[i], "wizard_answer:"+step.Options[i]),
+			)
+			if i+1 < len(step.Options) {
+				row = append(row, tgbotapi.NewInlineKeyboardButtonData(step.Options[i+1], "wizard_answer:"+step.Options[i+1]))
+			}
+			rows = append(rows, row)
+		}
+		rows = append(rows, wizardNavRow(step))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+	sendStepMessage(state, step, msg)
 }
 
-func deleteAllFormMessages(chatID int64, state *models.UserState) {
-	for _, msgID := range state.FormMessageIDs {
-		deleteMsg := tgbotapi.NewDeleteMessage(chatID, msgID)
-		Bot.Request(deleteMsg)
+const (
+	contactShareText  = "📱 Отправить мой номер"
+	contactBackText   = "⬅️ Назад"
+	contactCancelText = "❌ Отмена"
+)
+
+// contactKeyboard is the reply keyboard for phone steps: Telegram only
+// supports request_contact on reply buttons, so navigation goes there too.
+func contactKeyboard(step wizardStep) tgbotapi.ReplyKeyboardMarkup {
+	navRow := tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButton(contactCancelText))
+	if step.Prev != "" {
+		navRow = tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(contactBackText),
+			tgbotapi.NewKeyboardButton(contactCancelText),
+		)
+	}
+
+	keyboard := tgbotapi.NewReplyKeyboard(
+		tgbotapi.NewKeyboardButtonRow(tgbotapi.NewKeyboardButtonContact(contactShareText)),
+		navRow,
+	)
+	keyboard.OneTimeKeyboard = true
+	return keyboard
+}
+
+// hideContactKeyboard removes the reply keyboard once a phone step is left.
+func hideContactKeyboard(chatID int64, state *models.UserState, step wizardStep, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	sendStepMessage(state, step, msg)
+}
+
+// wizardNavRow builds the "⬅️ Назад" / "❌ Отмена" row shown under every step.
+func wizardNavRow(step wizardStep) []tgbotapi.InlineKeyboardButton {
+	if step.Prev == "" {
+		return tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "wizard_cancel"),
+		)
 	}
+	return tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬅️ Назад", "wizard_back"),
+		tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "wizard_cancel"),
+	)
 }
 
-func askFormQuestion(chatID int64, state *models.UserState, question string) {
-	msg := tgbotapi.NewMessage(chatID, question)
+func sendStepMessage(state *models.UserState, step wizardStep, msg tgbotapi.MessageConfig) {
 	sentMsg, err := Bot.Send(msg)
-	if err == nil {
+	if err == nil && step.Form {
 		state.FormMessageIDs = append(state.FormMessageIDs, sentMsg.MessageID)
 	}
 }
 
-func askFormCityQuestion(chatID int64, state *models.UserState) {
-	text := "Выберите ваш город или введите свой:"
+func finishJobWizard(chatID int64, userID int64, state *models.UserState) bool {
+	job := state.TempJob
+	job.CreatedBy = userID
+	job.Source = "telegram"
+	job.IsActive = true
+	delete(userStates, userID)
 
+	if company, err := database.GetCompanyByTelegramID(userID); err == nil {
+		job.CompanyID = &company.ID
+		job.CompanyVerified = company.Verified
+		job.Company = company.Name
+	}
+
+	if err := moderation.Check(job); err != nil {
+		msg := tgbotapi.NewMessage(chatID, "⚠️ "+err.Error())
+		Bot.Send(msg)
+		sendMainMenu(chatID)
+		return false
+	}
+
+	if err := database.SaveJob(job); err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении вакансии")
+		Bot.Send(msg)
+		sendMainMenu(chatID)
+		return false
+	}
+
+	go storeFile(job.PhotoFileID)
+
+	text := "✅ Вакансия успешно добавлена!"
+	if job.IsActive {
+		go PublishJob(*job)
+	} else {
+		text = "🕵️ Вакансия отправлена на проверку модератору и появится после одобрения."
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	Bot.Send(msg)
+	sendMainMenu(chatID)
+	return true
+}
+
+func finishFormWizard(chatID int64, userID int64, state *models.UserState) bool {
+	// Delete ALL collected messages at the end
+	deleteAllFormMessages(chatID, state)
+	delete(userStates, userID)
+
+	// Save form data and show confirmation
+	if err := saveFormData(userID, state); err != nil {
+		msg := tgbotapi.NewMessage(chatID, "Ошибка при сохранении анкеты")
+		Bot.Send(msg)
+		sendMainMenu(chatID)
+		return false
+	}
+	showFormSummary(chatID, state)
+	return true
+}
+
+func deleteAllFormMessages(chatID int64, state *models.UserState) {
+	for _, msgID := range state.FormMessageIDs {
+		deleteMsg := tgbotapi.NewDeleteMessage(chatID, msgID)
+		Bot.Request(deleteMsg)
+	}
+}
+
+func askFormCityQuestion(chatID int64, state *models.UserState, step wizardStep) {
 	var rows [][]tgbotapi.InlineKeyboardButton
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData("Бишкек", "form_city:Бишкек"),
@@ -120,20 +698,42 @@ func askFormCityQuestion(chatID int64, state *models.UserState) {
 		tgbotapi.NewInlineKeyboardButtonData("Жалал-Абад", "form_city:Жалал-Абад"),
 		tgbotapi.NewInlineKeyboardButtonData("Чолпон-Ата", "form_city:Чолпон-Ата"),
 	))
+	rows = append(rows, wizardNavRow(step))
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
-	msg := tgbotapi.NewMessage(chatID, text)
+	msg := tgbotapi.NewMessage(chatID, step.Prompt)
 	msg.ReplyMarkup = keyboard
-	sentMsg, err := Bot.Send(msg)
-	if err == nil {
-		state.FormMessageIDs = append(state.FormMessageIDs, sentMsg.MessageID)
-	}
+	sendStepMessage(state, step, msg)
 }
 
-func saveFormData(userID int64, state *models.UserState) {
-	database.UpdateUserFormData(userID, state.FormName, state.FormPhone, state.FormCity, state.FormSpecialty, state.FormExperience)
+func saveFormData(userID int64, state *models.UserState) error {
+	database.UpdateUserFormData(userID, state.FormName, state.FormPhone, state.FormCity, state.FormSpecialty, state.FormExperience, state.PhoneVerified)
 
 	username := database.GetUsernameByTelegramID(userID)
 
-	database.SaveResume(userID, username, state.FormName, state.FormPhone, state.FormCity, state.FormSpecialty, state.FormExperience)
+	err := database.SaveResume(&models.Resume{
+		TelegramID:      userID,
+		Username:        username,
+		Name:            state.FormName,
+		Phone:           state.FormPhone,
+		City:            state.FormCity,
+		Specialty:       state.FormSpecialty,
+		Experience:      state.FormExperience,
+		WorkHistory:     state.FormWorkHistory,
+		Education:       state.FormEducation,
+		Skills:          state.FormSkills,
+		DesiredSalary:   state.FormDesiredSalary,
+		ReadyToRelocate: state.FormRelocate,
+		Schedule:        state.FormSchedule,
+		PhotoFileID:     state.FormPhotoFileID,
+		CVFileID:        state.FormCVFileID,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	go storeFile(state.FormPhotoFileID)
+	go storeFile(state.FormCVFileID)
+	return nil
 }
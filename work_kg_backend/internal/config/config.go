@@ -1,16 +1,40 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	TelegramToken string
-	DatabaseURL   string
-	ServerPort    string
+	TelegramToken   string
+	DatabaseURL     string
+	ServerPort      string
+	BotUsername     string
+	Channels        []ChannelConfig
+	ReportThreshold int
+	MaxJobsPerDay   int
+	RiskThreshold   int
+	StorageDir      string
+	// Matching notifications: minimum score and how often to check, in minutes
+	MatchNotifyScore    int
+	MatchNotifyInterval int
+	// Hours after applying before a job seeker may review the employer
+	ReviewCooldownHours int
+	// Days a deleted job stays in the trash before it is purged, 0 keeps it forever
+	JobRetentionDays int
+}
+
+// ChannelConfig is a Telegram channel that new vacancies are published to.
+// Empty City/Category mean the channel receives jobs from every city/category.
+type ChannelConfig struct {
+	Chat     string
+	City     string
+	Category string
 }
 
 func Load() *Config {
@@ -20,9 +44,19 @@ func Load() *Config {
 	}
 
 	cfg := &Config{
-		TelegramToken: getEnv("TELEGRAM_TOKEN", ""),
-		DatabaseURL:   getEnv("DATABASE_URL", ""),
-		ServerPort:    getEnv("SERVER_PORT", "8080"),
+		TelegramToken:       getEnv("TELEGRAM_TOKEN", ""),
+		DatabaseURL:         getEnv("DATABASE_URL", ""),
+		ServerPort:          getEnv("SERVER_PORT", "8080"),
+		BotUsername:         getEnv("BOT_USERNAME", "work_kg_bot"),
+		Channels:            parseChannels(getEnv("TELEGRAM_CHANNELS", "")),
+		ReportThreshold:     getEnvInt("REPORT_THRESHOLD", 3),
+		MaxJobsPerDay:       getEnvInt("MAX_JOBS_PER_DAY", 5),
+		RiskThreshold:       getEnvInt("RISK_THRESHOLD", 70),
+		StorageDir:          getEnv("STORAGE_DIR", ""),
+		MatchNotifyScore:    getEnvInt("MATCH_NOTIFY_SCORE", 80),
+		MatchNotifyInterval: getEnvInt("MATCH_NOTIFY_INTERVAL", 60),
+		ReviewCooldownHours: getEnvInt("REVIEW_COOLDOWN_HOURS", 72),
+		JobRetentionDays:    getEnvInt("JOB_RETENTION_DAYS", 30),
 	}
 
 	// Validate required fields
@@ -36,9 +70,54 @@ func Load() *Config {
 	return cfg
 }
 
+// JobLink is the bot deep link that opens the given job. The CRM and the bot
+// both build links with it, so they always point at the same bot.
+func (c *Config) JobLink(jobID int64) string {
+	return fmt.Sprintf("https://t.me/%s?start=job_%d", c.BotUsername, jobID)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// parseChannels reads a list like "@work_kg;@work_kg_osh|city=Ош;-100123|category=IT"
+func parseChannels(value string) []ChannelConfig {
+	var channels []ChannelConfig
+	for _, entry := range strings.Split(value, ";") {
+		fields := strings.Split(strings.TrimSpace(entry), "|")
+		if fields[0] == "" {
+			continue
+		}
+
+		channel := ChannelConfig{Chat: fields[0]}
+		for _, filter := range fields[1:] {
+			key, val, _ := strings.Cut(filter, "=")
+			switch strings.TrimSpace(key) {
+			case "city":
+				channel.City = strings.TrimSpace(val)
+			case "category":
+				channel.Category = strings.TrimSpace(val)
+			default:
+				log.Printf("Unknown channel filter %q for %s", key, channel.Chat)
+			}
+		}
+		channels = append(channels, channel)
+	}
+	return channels
+}
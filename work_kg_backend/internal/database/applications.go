@@ -0,0 +1,63 @@
+package database
+
+import (
+	"log"
+	"time"
+
+	"work_kg_backend/internal/models"
+)
+
+// SaveApplication records that a user responded to a job. It returns false
+// when the user had already applied.
+func SaveApplication(jobID, telegramID int64) (bool, error) {
+	result, err := DB.Exec(`INSERT INTO applications (job_id, telegram_id) VALUES ($1, $2)
+		ON CONFLICT (job_id, telegram_id) DO NOTHING`, jobID, telegramID)
+	if err != nil {
+		log.Printf("Error saving application: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+func GetApplication(jobID, telegramID int64) (*models.Application, error) {
+	var application models.Application
+	err := DB.QueryRow(`SELECT id, job_id, telegram_id, created_at FROM applications
+		WHERE job_id = $1 AND telegram_id = $2`, jobID, telegramID).Scan(
+		&application.ID, &application.JobID, &application.TelegramID, &application.CreatedAt)
+	return &application, err
+}
+
+// GetApplicationsAwaitingReview returns applications made before the given
+// time whose applicant hasn't been asked to review the employer yet
+func GetApplicationsAwaitingReview(appliedBefore time.Time, limit int) ([]models.Application, error) {
+	rows, err := DB.Query(`SELECT a.id, a.job_id, a.telegram_id, a.created_at
+		FROM applications a JOIN jobs j ON j.id = a.job_id
+		WHERE a.review_requested_at IS NULL AND a.created_at <= $1 AND j.deleted_at IS NULL
+		AND (j.company_id IS NOT NULL OR COALESCE(j.created_by, 0) <> 0)
+		ORDER BY a.created_at LIMIT $2`, appliedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applications []models.Application
+	for rows.Next() {
+		var application models.Application
+		if err := rows.Scan(&application.ID, &application.JobID, &application.TelegramID, &application.CreatedAt); err != nil {
+			log.Printf("Error scanning application: %v", err)
+			continue
+		}
+		applications = append(applications, application)
+	}
+
+	return applications, nil
+}
+
+func MarkReviewRequested(applicationID int64) error {
+	_, err := DB.Exec(`UPDATE applications SET review_requested_at = CURRENT_TIMESTAMP WHERE id = $1`, applicationID)
+	if err != nil {
+		log.Printf("Error marking review requested: %v", err)
+	}
+	return err
+}
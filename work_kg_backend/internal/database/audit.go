@@ -0,0 +1,99 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+func SaveAuditEntry(entry *models.AuditEntry) error {
+	changes, _ := json.Marshal(entry.Changes)
+	err := DB.QueryRow(`INSERT INTO audit_log (admin_id, admin_email, action, entity_type, entity_id, before, after, changes,
+		method, path, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, created_at`,
+		entry.AdminID, entry.AdminEmail, entry.Action, entry.EntityType, entry.EntityID,
+		nullJSON(entry.Before), nullJSON(entry.After), string(changes),
+		entry.Method, entry.Path, entry.IPAddress, entry.UserAgent,
+	).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		log.Printf("Error saving audit entry: %v", err)
+	}
+	return err
+}
+
+func GetAuditLog(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	query := `SELECT id, admin_id, COALESCE(admin_email, ''), action, entity_type, COALESCE(entity_id, ''),
+		COALESCE(before::TEXT, 'null'), COALESCE(after::TEXT, 'null'), COALESCE(changes::TEXT, '{}'),
+		COALESCE(method, ''), COALESCE(path, ''), COALESCE(ip_address, ''), COALESCE(user_agent, ''), created_at
+		FROM audit_log WHERE 1=1`
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.AdminID != 0 {
+		query += fmt.Sprintf(" AND admin_id = $%d", argNum)
+		args = append(args, filter.AdminID)
+		argNum++
+	}
+	if filter.Action != "" {
+		query += fmt.Sprintf(" AND action = $%d", argNum)
+		args = append(args, filter.Action)
+		argNum++
+	}
+	if filter.EntityType != "" {
+		query += fmt.Sprintf(" AND entity_type = $%d", argNum)
+		args = append(args, filter.EntityType)
+		argNum++
+	}
+	if filter.EntityID != "" {
+		query += fmt.Sprintf(" AND entity_id = $%d", argNum)
+		args = append(args, filter.EntityID)
+		argNum++
+	}
+	if !filter.From.IsZero() {
+		query += fmt.Sprintf(" AND created_at >= $%d", argNum)
+		args = append(args, filter.From)
+		argNum++
+	}
+	if !filter.To.IsZero() {
+		query += fmt.Sprintf(" AND created_at < $%d", argNum)
+		args = append(args, filter.To)
+		argNum++
+	}
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argNum, argNum+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditEntry, 0)
+	for rows.Next() {
+		var entry models.AuditEntry
+		var before, after, changes string
+		err := rows.Scan(&entry.ID, &entry.AdminID, &entry.AdminEmail, &entry.Action, &entry.EntityType, &entry.EntityID,
+			&before, &after, &changes, &entry.Method, &entry.Path, &entry.IPAddress, &entry.UserAgent, &entry.CreatedAt)
+		if err != nil {
+			log.Printf("Error scanning audit entry: %v", err)
+			continue
+		}
+		entry.Before = json.RawMessage(before)
+		entry.After = json.RawMessage(after)
+		json.Unmarshal([]byte(changes), &entry.Changes)
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// nullJSON stores an empty document as SQL NULL
+func nullJSON(data json.RawMessage) interface{} {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return string(data)
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+func GetBlocklist() ([]models.BlocklistEntry, error) {
+	rows, err := DB.Query(`SELECT id, kind, value, created_at FROM blocklist ORDER BY kind, value`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]models.BlocklistEntry, 0)
+	for rows.Next() {
+		var entry models.BlocklistEntry
+		if err := rows.Scan(&entry.ID, &entry.Kind, &entry.Value, &entry.CreatedAt); err != nil {
+			log.Printf("Error scanning blocklist entry: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func GetBlocklistEntry(id int64) (*models.BlocklistEntry, error) {
+	var entry models.BlocklistEntry
+	err := DB.QueryRow(`SELECT id, kind, value, created_at FROM blocklist WHERE id = $1`, id).Scan(
+		&entry.ID, &entry.Kind, &entry.Value, &entry.CreatedAt)
+	return &entry, err
+}
+
+func AddBlocklistEntry(entry *models.BlocklistEntry) error {
+	return DB.QueryRow(`INSERT INTO blocklist (kind, value) VALUES ($1, $2)
+		ON CONFLICT (kind, value) DO UPDATE SET value = EXCLUDED.value RETURNING id, created_at`,
+		entry.Kind, entry.Value).Scan(&entry.ID, &entry.CreatedAt)
+}
+
+func DeleteBlocklistEntry(id int64) error {
+	_, err := DB.Exec(`DELETE FROM blocklist WHERE id = $1`, id)
+	return err
+}
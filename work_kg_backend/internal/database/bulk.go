@@ -0,0 +1,150 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"work_kg_backend/internal/models"
+)
+
+// BulkUpdateJobs applies a bulk action to the selected jobs in one
+// transaction. It returns how many jobs matched and the state before the
+// change of those that were actually changed; jobs already in the target
+// state are left alone.
+func BulkUpdateJobs(req models.JobBulkRequest) (int, []models.Job, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	query, args := bulkJobsQuery(req)
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		log.Printf("Error selecting jobs for bulk %s: %v", req.Action, err)
+		return 0, nil, err
+	}
+
+	var matched []models.Job
+	for rows.Next() {
+		var job models.Job
+		if err := rows.Scan(&job.ID, &job.Title, &job.Category, &job.Subcategory, &job.IsActive, &job.CreatedAt, &job.BumpedAt); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		matched = append(matched, job)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	var changed []models.Job
+	var ids []int64
+	for _, job := range matched {
+		if bulkChanges(req, job) {
+			changed = append(changed, job)
+			ids = append(ids, job.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return len(matched), nil, nil
+	}
+
+	switch req.Action {
+	case models.BulkActivate, models.BulkDeactivate:
+		_, err = tx.Exec(`UPDATE jobs SET is_active = $1, deactivation_reason = NULL WHERE id = ANY($2)`, req.Action == models.BulkActivate, pq.Array(ids))
+	case models.BulkDelete:
+		_, err = tx.Exec(`UPDATE jobs SET deleted_at = CURRENT_TIMESTAMP WHERE id = ANY($1)`, pq.Array(ids))
+	case models.BulkRecategorize:
+		_, err = tx.Exec(`UPDATE jobs SET category = $1, subcategory = $2 WHERE id = ANY($3)`, req.Category, req.Subcategory, pq.Array(ids))
+	case models.BulkExtend:
+		_, err = tx.Exec(`UPDATE jobs SET bumped_at = CURRENT_TIMESTAMP WHERE id = ANY($1)`, pq.Array(ids))
+	default:
+		err = fmt.Errorf("unknown bulk action %q", req.Action)
+	}
+	if err != nil {
+		log.Printf("Error applying bulk %s: %v", req.Action, err)
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return len(matched), changed, nil
+}
+
+// bulkJobsQuery selects and locks the live jobs a bulk request targets
+func bulkJobsQuery(req models.JobBulkRequest) (string, []interface{}) {
+	query := `SELECT id, title, category, subcategory, is_active, created_at, bumped_at FROM jobs WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	argNum := 1
+
+	if len(req.IDs) > 0 {
+		query += fmt.Sprintf(" AND id = ANY($%d)", argNum)
+		args = append(args, pq.Array(req.IDs))
+		argNum++
+	}
+
+	if filter := req.Filter; filter != nil {
+		if filter.Source != "" {
+			query += fmt.Sprintf(" AND source = $%d", argNum)
+			args = append(args, filter.Source)
+			argNum++
+		}
+		if filter.Category != "" {
+			query += fmt.Sprintf(" AND category = $%d", argNum)
+			args = append(args, filter.Category)
+			argNum++
+		}
+		if filter.Subcategory != "" {
+			query += fmt.Sprintf(" AND subcategory = $%d", argNum)
+			args = append(args, filter.Subcategory)
+			argNum++
+		}
+		if filter.City != "" {
+			query += fmt.Sprintf(" AND city = $%d", argNum)
+			args = append(args, filter.City)
+			argNum++
+		}
+		if filter.CompanyID != nil {
+			query += fmt.Sprintf(" AND company_id = $%d", argNum)
+			args = append(args, *filter.CompanyID)
+			argNum++
+		}
+		if filter.IsActive != nil {
+			query += fmt.Sprintf(" AND is_active = $%d", argNum)
+			args = append(args, *filter.IsActive)
+			argNum++
+		}
+		if filter.OlderThanDays > 0 {
+			query += fmt.Sprintf(" AND %s < $%d", jobFreshnessColumn, argNum)
+			args = append(args, time.Now().AddDate(0, 0, -filter.OlderThanDays))
+			argNum++
+		}
+		if filter.MinRisk > 0 {
+			query += fmt.Sprintf(" AND risk_score >= $%d", argNum)
+			args = append(args, filter.MinRisk)
+			argNum++
+		}
+	}
+
+	query += " ORDER BY id FOR UPDATE"
+	return query, args
+}
+
+// bulkChanges reports whether the action would change the job
+func bulkChanges(req models.JobBulkRequest, job models.Job) bool {
+	switch req.Action {
+	case models.BulkActivate:
+		return !job.IsActive
+	case models.BulkDeactivate:
+		return job.IsActive
+	case models.BulkRecategorize:
+		return job.Category != req.Category || job.Subcategory != req.Subcategory
+	default:
+		return true
+	}
+}
@@ -0,0 +1,179 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+// candidateColumns selects the CRM pipeline status and tags for the
+// telegram_id of the given table
+func candidateColumns(table string) string {
+	return `COALESCE((SELECT status FROM candidates WHERE candidates.telegram_id = ` + table + `.telegram_id), 'new'),
+		ARRAY(SELECT tag FROM candidate_tags WHERE candidate_tags.telegram_id = ` + table + `.telegram_id ORDER BY tag)`
+}
+
+// SetCandidateStatus moves the candidate to a pipeline status and records
+// the change in their timeline
+func SetCandidateStatus(telegramID, adminID int64, status string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO candidates (telegram_id, status, updated_by) VALUES ($1, $2, $3)
+		ON CONFLICT (telegram_id) DO UPDATE SET status = EXCLUDED.status, updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP`,
+		telegramID, status, adminID)
+	if err != nil {
+		log.Printf("Error setting candidate status: %v", err)
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO candidate_events (telegram_id, admin_id, kind, details) VALUES ($1, $2, 'status_changed', $3)`,
+		telegramID, adminID, status); err != nil {
+		log.Printf("Error saving candidate event: %v", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AddCandidateTag tags the candidate. It returns false when the tag was already set.
+func AddCandidateTag(telegramID, adminID int64, tag string) (bool, error) {
+	tx, err := DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO candidate_tags (telegram_id, tag, created_by) VALUES ($1, $2, $3)
+		ON CONFLICT (telegram_id, tag) DO NOTHING`, telegramID, tag, adminID)
+	if err != nil {
+		log.Printf("Error adding candidate tag: %v", err)
+		return false, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+	if _, err := tx.Exec(`INSERT INTO candidate_events (telegram_id, admin_id, kind, details) VALUES ($1, $2, 'tag_added', $3)`,
+		telegramID, adminID, tag); err != nil {
+		log.Printf("Error saving candidate event: %v", err)
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+func RemoveCandidateTag(telegramID, adminID int64, tag string) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM candidate_tags WHERE telegram_id = $1 AND tag = $2`, telegramID, tag)
+	if err != nil {
+		log.Printf("Error removing candidate tag: %v", err)
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(`INSERT INTO candidate_events (telegram_id, admin_id, kind, details) VALUES ($1, $2, 'tag_removed', $3)`,
+		telegramID, adminID, tag); err != nil {
+		log.Printf("Error saving candidate event: %v", err)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func AddCandidateNote(note *models.CandidateNote) error {
+	err := DB.QueryRow(`INSERT INTO candidate_notes (telegram_id, admin_id, text) VALUES ($1, $2, $3)
+		RETURNING id, created_at, COALESCE((SELECT name FROM admin_users WHERE id = $2), '')`,
+		note.TelegramID, note.AdminID, note.Text).Scan(&note.ID, &note.CreatedAt, &note.AdminName)
+	if err != nil {
+		log.Printf("Error adding candidate note: %v", err)
+	}
+	return err
+}
+
+func GetCandidateNotes(telegramID int64) ([]models.CandidateNote, error) {
+	rows, err := DB.Query(`SELECT n.id, n.telegram_id, COALESCE(n.admin_id, 0), COALESCE(a.name, ''), n.text, n.created_at
+		FROM candidate_notes n LEFT JOIN admin_users a ON a.id = n.admin_id
+		WHERE n.telegram_id = $1 ORDER BY n.created_at DESC`, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := make([]models.CandidateNote, 0)
+	for rows.Next() {
+		var note models.CandidateNote
+		if err := rows.Scan(&note.ID, &note.TelegramID, &note.AdminID, &note.AdminName, &note.Text, &note.CreatedAt); err != nil {
+			log.Printf("Error scanning candidate note: %v", err)
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	return notes, nil
+}
+
+// DeleteCandidateNote removes a note. Only its author can delete it unless
+// anyAuthor is set. It returns false when no such note was found.
+func DeleteCandidateNote(telegramID, noteID, adminID int64, anyAuthor bool) (bool, error) {
+	result, err := DB.Exec(`DELETE FROM candidate_notes WHERE id = $1 AND telegram_id = $2 AND ($3 OR admin_id = $4)`,
+		noteID, telegramID, anyAuthor, adminID)
+	if err != nil {
+		log.Printf("Error deleting candidate note: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// GetCandidateTimeline merges everything that happened with the candidate,
+// newest first: registration, resume updates, applications, contact
+// requests and views, recruiter notes, tag and status changes
+func GetCandidateTimeline(telegramID int64) ([]models.TimelineEvent, error) {
+	rows, err := DB.Query(`SELECT events.kind, events.details, events.admin_id, COALESCE(a.name, ''), events.created_at FROM (
+			SELECT 'registered' AS kind, '' AS details, NULL::INTEGER AS admin_id, created_at
+			FROM users WHERE telegram_id = $1
+		UNION ALL
+			SELECT 'resume_updated', COALESCE(specialty, ''), NULL, updated_at
+			FROM resumes WHERE telegram_id = $1
+		UNION ALL
+			SELECT 'applied', COALESCE(j.title, ''), NULL, ap.created_at
+			FROM applications ap LEFT JOIN jobs j ON j.id = ap.job_id WHERE ap.telegram_id = $1
+		UNION ALL
+			SELECT 'contact_requested', cr.status, cr.admin_id, cr.created_at
+			FROM contact_requests cr JOIN resumes r ON r.id = cr.resume_id WHERE r.telegram_id = $1
+		UNION ALL
+			SELECT 'contact_viewed', '', cv.admin_id, cv.viewed_at
+			FROM contact_views cv JOIN resumes r ON r.id = cv.resume_id WHERE r.telegram_id = $1
+		UNION ALL
+			SELECT 'note', text, admin_id, created_at
+			FROM candidate_notes WHERE telegram_id = $1
+		UNION ALL
+			SELECT kind, COALESCE(details, ''), admin_id, created_at
+			FROM candidate_events WHERE telegram_id = $1
+		) events LEFT JOIN admin_users a ON a.id = events.admin_id
+		ORDER BY events.created_at DESC`, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]models.TimelineEvent, 0)
+	for rows.Next() {
+		var event models.TimelineEvent
+		if err := rows.Scan(&event.Kind, &event.Details, &event.AdminID, &event.AdminName, &event.CreatedAt); err != nil {
+			log.Printf("Error scanning timeline event: %v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
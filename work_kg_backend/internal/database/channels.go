@@ -0,0 +1,42 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+func SaveChannelPost(jobID int64, channel string, messageID int) error {
+	_, err := DB.Exec(`INSERT INTO channel_posts (job_id, channel, message_id) VALUES ($1, $2, $3)
+		ON CONFLICT (job_id, channel) DO UPDATE SET message_id = EXCLUDED.message_id`,
+		jobID, channel, messageID)
+	if err != nil {
+		log.Printf("Error saving channel post: %v", err)
+	}
+	return err
+}
+
+func GetChannelPosts(jobID int64) ([]models.ChannelPost, error) {
+	rows, err := DB.Query(`SELECT job_id, channel, message_id FROM channel_posts WHERE job_id = $1`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.ChannelPost
+	for rows.Next() {
+		var post models.ChannelPost
+		if err := rows.Scan(&post.JobID, &post.Channel, &post.MessageID); err != nil {
+			log.Printf("Error scanning channel post: %v", err)
+			continue
+		}
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+func DeleteChannelPost(jobID int64, channel string) error {
+	_, err := DB.Exec(`DELETE FROM channel_posts WHERE job_id = $1 AND channel = $2`, jobID, channel)
+	return err
+}
@@ -0,0 +1,182 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+const companyColumns = `c.id, c.name, COALESCE(c.description, ''), COALESCE(c.logo_url, ''), COALESCE(c.phone, ''),
+	COALESCE(c.email, ''), COALESCE(c.website, ''), COALESCE(c.verified, false), c.verified_at, c.created_at,
+	(SELECT COUNT(*) FROM jobs j WHERE j.company_id = c.id AND j.is_active = true AND j.deleted_at IS NULL),
+	COALESCE((SELECT AVG(rating) FROM employer_reviews r WHERE r.employer_company_id = c.id AND r.status = 'approved'), 0),
+	(SELECT COUNT(*) FROM employer_reviews r WHERE r.employer_company_id = c.id AND r.status = 'approved')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCompany(row rowScanner, company *models.Company) error {
+	return row.Scan(&company.ID, &company.Name, &company.Description, &company.LogoURL, &company.Phone,
+		&company.Email, &company.Website, &company.Verified, &company.VerifiedAt, &company.CreatedAt,
+		&company.ActiveJobs, &company.Rating, &company.ReviewCount)
+}
+
+func GetCompanies() ([]models.Company, error) {
+	rows, err := DB.Query(`SELECT ` + companyColumns + ` FROM companies c ORDER BY c.name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	companies := make([]models.Company, 0)
+	for rows.Next() {
+		var company models.Company
+		if err := scanCompany(rows, &company); err != nil {
+			log.Printf("Error scanning company: %v", err)
+			continue
+		}
+		companies = append(companies, company)
+	}
+
+	return companies, nil
+}
+
+func GetCompanyByID(id int64) (*models.Company, error) {
+	var company models.Company
+	err := scanCompany(DB.QueryRow(`SELECT `+companyColumns+` FROM companies c WHERE c.id = $1`, id), &company)
+	return &company, err
+}
+
+// GetCompanyByTelegramID returns the company the bot user is linked to
+func GetCompanyByTelegramID(telegramID int64) (*models.Company, error) {
+	var company models.Company
+	err := scanCompany(DB.QueryRow(`SELECT `+companyColumns+` FROM companies c
+		JOIN users u ON u.company_id = c.id WHERE u.telegram_id = $1`, telegramID), &company)
+	return &company, err
+}
+
+func CreateCompany(company *models.Company) error {
+	return DB.QueryRow(`INSERT INTO companies (name, description, logo_url, phone, email, website)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+		company.Name, company.Description, company.LogoURL, company.Phone, company.Email, company.Website,
+	).Scan(&company.ID, &company.CreatedAt)
+}
+
+// UpdateCompany changes the company profile and keeps the jobs' company name in sync
+func UpdateCompany(id int64, company *models.Company) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`UPDATE companies SET name=$1, description=$2, logo_url=$3, phone=$4, email=$5, website=$6 WHERE id=$7`,
+		company.Name, company.Description, company.LogoURL, company.Phone, company.Email, company.Website, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE jobs SET company = $1 WHERE company_id = $2`, company.Name, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func DeleteCompany(id int64) error {
+	_, err := DB.Exec(`DELETE FROM companies WHERE id = $1`, id)
+	return err
+}
+
+func SetCompanyVerified(id int64, verified bool) error {
+	_, err := DB.Exec(`UPDATE companies SET verified = $1,
+		verified_at = CASE WHEN $1 THEN CURRENT_TIMESTAMP ELSE NULL END WHERE id = $2`, verified, id)
+	if err != nil {
+		log.Printf("Error verifying company: %v", err)
+	}
+	return err
+}
+
+// LinkUserToCompany makes the bot user a member of the company, or removes
+// them from any company when companyID is nil. It returns false when the
+// user is unknown.
+func LinkUserToCompany(telegramID int64, companyID *int64) (bool, error) {
+	result, err := DB.Exec(`UPDATE users SET company_id = $1 WHERE telegram_id = $2`, companyID, telegramID)
+	if err != nil {
+		log.Printf("Error linking user to company: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// UnlinkUserFromCompany detaches the user from the company, reporting
+// whether they were a member of it
+func UnlinkUserFromCompany(telegramID, companyID int64) (bool, error) {
+	result, err := DB.Exec(`UPDATE users SET company_id = NULL WHERE telegram_id = $1 AND company_id = $2`, telegramID, companyID)
+	if err != nil {
+		log.Printf("Error unlinking user from company: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// GetCompanyMembers lists the bot users linked to the company
+func GetCompanyMembers(companyID int64) ([]models.User, error) {
+	rows, err := DB.Query(`SELECT id, telegram_id, COALESCE(username, ''), COALESCE(first_name, ''),
+		COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(phone_verified, false), company_id, COALESCE(city, ''),
+		COALESCE(specialty, ''), COALESCE(experience, ''), role, created_at, `+resumePrivacyColumns+`
+		FROM users WHERE company_id = $1 ORDER BY created_at`, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName,
+			&user.Phone, &user.PhoneVerified, &user.CompanyID, &user.City, &user.Specialty, &user.Experience, &user.Role, &user.CreatedAt,
+			&user.ResumeVisibility, &user.ResumeAnonymous)
+		if err != nil {
+			log.Printf("Error scanning company member: %v", err)
+			continue
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// GetCompanyJobs lists the company's vacancies, newest first
+func GetCompanyJobs(companyID int64, activeOnly bool) ([]models.Job, error) {
+	query := `SELECT j.id, j.title, j.description, j.category, j.subcategory, j.city, j.salary, j.phone, j.company,
+		COALESCE(j.photo_file_id, ''), j.is_active, j.company_id, COALESCE(c.verified, false), ` + employerRatingColumns + `, j.created_at
+		FROM jobs j JOIN companies c ON c.id = j.company_id WHERE j.company_id = $1 AND j.deleted_at IS NULL`
+	if activeOnly {
+		query += " AND j.is_active = true"
+	}
+	query += " ORDER BY j.created_at DESC"
+
+	rows, err := DB.Query(query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		var job models.Job
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company,
+			&job.PhotoFileID, &job.IsActive, &job.CompanyID, &job.CompanyVerified,
+			&job.EmployerRating, &job.EmployerReviews, &job.CreatedAt)
+		if err != nil {
+			log.Printf("Error scanning company job: %v", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
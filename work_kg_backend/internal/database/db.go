@@ -6,6 +6,7 @@ import (
 
 	_ "github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
+	"work_kg_backend/internal/models"
 )
 
 var DB *sql.DB
@@ -48,6 +49,7 @@ func InitSchema() {
 		)`,
 		`ALTER TABLE users ADD COLUMN IF NOT EXISTS specialty VARCHAR(255)`,
 		`ALTER TABLE users ADD COLUMN IF NOT EXISTS experience TEXT`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN DEFAULT false`,
 		`CREATE TABLE IF NOT EXISTS admin_users (
 			id SERIAL PRIMARY KEY,
 			email VARCHAR(255) UNIQUE NOT NULL,
@@ -71,6 +73,14 @@ func InitSchema() {
 			source VARCHAR(50) DEFAULT 'telegram',
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS photo_file_id VARCHAR(255)`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS risk_score INTEGER DEFAULT 0`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS risk_flags TEXT[] DEFAULT '{}'`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS bumped_at TIMESTAMP`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS deactivation_reason VARCHAR(50)`,
 		`CREATE TABLE IF NOT EXISTS resumes (
 			id SERIAL PRIMARY KEY,
 			telegram_id BIGINT UNIQUE,
@@ -83,10 +93,186 @@ func InitSchema() {
 			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS applications (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+			telegram_id BIGINT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (job_id, telegram_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS channel_posts (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+			channel VARCHAR(255) NOT NULL,
+			message_id INTEGER NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (job_id, channel)
+		)`,
+		`CREATE TABLE IF NOT EXISTS saved_jobs (
+			id SERIAL PRIMARY KEY,
+			telegram_id BIGINT NOT NULL,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (telegram_id, job_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS job_reports (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+			telegram_id BIGINT NOT NULL,
+			reason VARCHAR(50) NOT NULL,
+			status VARCHAR(20) DEFAULT 'pending',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (job_id, telegram_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS blocklist (
+			id SERIAL PRIMARY KEY,
+			kind VARCHAR(20) NOT NULL,
+			value VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (kind, value)
+		)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS education TEXT`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS skills TEXT[] DEFAULT '{}'`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS desired_salary VARCHAR(100)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS ready_to_relocate BOOLEAN DEFAULT false`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS schedule VARCHAR(50)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS photo_file_id VARCHAR(255)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS cv_file_id VARCHAR(255)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS visibility VARCHAR(20) DEFAULT 'public'`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS anonymous BOOLEAN DEFAULT false`,
+		`CREATE TABLE IF NOT EXISTS contact_requests (
+			id SERIAL PRIMARY KEY,
+			resume_id INTEGER REFERENCES resumes(id) ON DELETE CASCADE,
+			admin_id INTEGER REFERENCES admin_users(id) ON DELETE CASCADE,
+			status VARCHAR(20) DEFAULT 'pending',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			responded_at TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS contact_views (
+			id SERIAL PRIMARY KEY,
+			resume_id INTEGER REFERENCES resumes(id) ON DELETE CASCADE,
+			admin_id INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			ip_address VARCHAR(100),
+			viewed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS resume_work_history (
+			id SERIAL PRIMARY KEY,
+			resume_id INTEGER REFERENCES resumes(id) ON DELETE CASCADE,
+			employer VARCHAR(255),
+			position VARCHAR(255),
+			period VARCHAR(100),
+			sort_order INTEGER DEFAULT 0
+		)`,
+		`ALTER TABLE resumes ADD COLUMN IF NOT EXISTS notify_matches BOOLEAN DEFAULT true`,
+		`CREATE TABLE IF NOT EXISTS match_notifications (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+			telegram_id BIGINT NOT NULL,
+			score INTEGER NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE (job_id, telegram_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS companies (
+			id SERIAL PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			logo_url VARCHAR(500),
+			phone VARCHAR(50),
+			email VARCHAR(255),
+			website VARCHAR(255),
+			verified BOOLEAN DEFAULT false,
+			verified_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS company_id INTEGER REFERENCES companies(id) ON DELETE SET NULL`,
+		`CREATE TABLE IF NOT EXISTS employer_reviews (
+			id SERIAL PRIMARY KEY,
+			job_id INTEGER REFERENCES jobs(id) ON DELETE SET NULL,
+			employer_company_id INTEGER REFERENCES companies(id) ON DELETE CASCADE,
+			employer_telegram_id BIGINT,
+			telegram_id BIGINT NOT NULL,
+			rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+			comment TEXT,
+			status VARCHAR(20) DEFAULT 'pending',
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			moderated_at TIMESTAMP,
+			UNIQUE (job_id, telegram_id)
+		)`,
+		`ALTER TABLE applications ADD COLUMN IF NOT EXISTS review_requested_at TIMESTAMP`,
+		`CREATE TABLE IF NOT EXISTS candidates (
+			telegram_id BIGINT PRIMARY KEY,
+			status VARCHAR(30) DEFAULT 'new',
+			updated_by INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS candidate_tags (
+			telegram_id BIGINT NOT NULL,
+			tag VARCHAR(50) NOT NULL,
+			created_by INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (telegram_id, tag)
+		)`,
+		`CREATE TABLE IF NOT EXISTS candidate_notes (
+			id SERIAL PRIMARY KEY,
+			telegram_id BIGINT NOT NULL,
+			admin_id INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			text TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS candidate_events (
+			id SERIAL PRIMARY KEY,
+			telegram_id BIGINT NOT NULL,
+			admin_id INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			kind VARCHAR(50) NOT NULL,
+			details TEXT,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id SERIAL PRIMARY KEY,
+			admin_id INTEGER REFERENCES admin_users(id) ON DELETE SET NULL,
+			admin_email VARCHAR(255),
+			action VARCHAR(50) NOT NULL,
+			entity_type VARCHAR(50) NOT NULL,
+			entity_id VARCHAR(100),
+			before JSONB,
+			after JSONB,
+			changes JSONB,
+			method VARCHAR(10),
+			path VARCHAR(500),
+			ip_address VARCHAR(100),
+			user_agent VARCHAR(500),
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS bot_events (
+			id BIGSERIAL PRIMARY KEY,
+			telegram_id BIGINT NOT NULL,
+			event VARCHAR(50) NOT NULL,
+			name VARCHAR(100) NOT NULL DEFAULT '',
+			properties JSONB,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)`,
 		`CREATE INDEX IF NOT EXISTS idx_resumes_telegram_id ON resumes(telegram_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_jobs_category ON jobs(category)`,
 		`CREATE INDEX IF NOT EXISTS idx_jobs_city ON jobs(city)`,
 		`CREATE INDEX IF NOT EXISTS idx_jobs_is_active ON jobs(is_active)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_created_by ON jobs(created_by)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_phone ON jobs(phone)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_company_id ON jobs(company_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_employer_reviews_company ON employer_reviews(employer_company_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_employer_reviews_employer ON employer_reviews(employer_telegram_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_candidate_notes_telegram_id ON candidate_notes(telegram_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_candidate_events_telegram_id ON candidate_events(telegram_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_admin_id ON audit_log(admin_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_deleted_at ON jobs(deleted_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_jobs_created_at ON jobs(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_resumes_created_at ON resumes(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_applications_created_at ON applications(created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_bot_events_event ON bot_events(event, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_bot_events_created_at ON bot_events(created_at)`,
 	}
 
 	for _, query := range queries {
@@ -96,6 +282,15 @@ func InitSchema() {
 		}
 	}
 
+	// Place existing jobs at their city center until they get exact coordinates
+	for city, point := range models.CityLocations {
+		_, err := DB.Exec(`UPDATE jobs SET latitude = $1, longitude = $2 WHERE city = $3 AND latitude IS NULL`,
+			point.Latitude, point.Longitude, city)
+		if err != nil {
+			log.Printf("Error setting job coordinates: %v", err)
+		}
+	}
+
 	// Create default admin user
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.DefaultCost)
 	_, err := DB.Exec(`INSERT INTO admin_users (email, password, name, role)
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"work_kg_backend/internal/models"
+)
+
+func SaveBotEvent(event *models.BotEvent) error {
+	var properties []byte
+	if len(event.Properties) > 0 {
+		properties, _ = json.Marshal(event.Properties)
+	}
+	err := DB.QueryRow(`INSERT INTO bot_events (telegram_id, event, name, properties) VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`, event.TelegramID, event.Event, event.Name, nullJSON(properties)).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		log.Printf("Error saving bot event: %v", err)
+	}
+	return err
+}
+
+// GetFunnel counts, per step of the wizard, the distinct users who reached
+// it and the invalid answers given there, plus how many completed or
+// cancelled it. Steps come back unordered, keyed by state name.
+func GetFunnel(wizard string, from, to time.Time) (*models.Funnel, map[string]models.FunnelStep, error) {
+	rows, err := DB.Query(`SELECT event, name, COUNT(DISTINCT telegram_id), COUNT(*) FROM bot_events
+		WHERE event IN ($1, $2, $3, $4) AND properties->>'wizard' = $5 AND created_at >= $6 AND created_at < $7
+		GROUP BY event, name`,
+		models.EventWizardStep, models.EventWizardError, models.EventWizardComplete, models.EventWizardCancel, wizard, from, to)
+	if err != nil {
+		log.Printf("Error loading funnel: %v", err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	funnel := &models.Funnel{Wizard: wizard}
+	steps := make(map[string]models.FunnelStep)
+	for rows.Next() {
+		var event, name string
+		var users, total int
+		if err := rows.Scan(&event, &name, &users, &total); err != nil {
+			log.Printf("Error scanning funnel: %v", err)
+			continue
+		}
+
+		step := steps[name]
+		step.Step = name
+		switch event {
+		case models.EventWizardStep:
+			step.Users = users
+			steps[name] = step
+		case models.EventWizardError:
+			step.Errors = total
+			steps[name] = step
+		case models.EventWizardComplete:
+			funnel.Completed += users
+		case models.EventWizardCancel:
+			funnel.Cancelled += users
+		}
+	}
+
+	return funnel, steps, rows.Err()
+}
+
+// GetSearchStats groups the searches made in the bot, most frequent first
+func GetSearchStats(filter models.SearchStatFilter) ([]models.SearchStat, error) {
+	query := `SELECT name, COALESCE(properties->>'category', ''), COALESCE(properties->>'subcategory', ''),
+		COALESCE(properties->>'city', ''), COALESCE(properties->>'query', ''),
+		COUNT(*), COUNT(DISTINCT telegram_id), COUNT(*) FILTER (WHERE (properties->>'results')::INT = 0), MAX(created_at)
+		FROM bot_events WHERE event = $1 AND created_at >= $2 AND created_at < $3`
+	args := []interface{}{models.EventSearch, filter.From, filter.To}
+	argNum := 4
+
+	if filter.ZeroResults {
+		query += " AND (properties->>'results')::INT = 0"
+	}
+
+	query += fmt.Sprintf(" GROUP BY 1, 2, 3, 4, 5 ORDER BY 6 DESC, 9 DESC LIMIT $%d", argNum)
+	args = append(args, filter.Limit)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		log.Printf("Error loading search stats: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]models.SearchStat, 0)
+	for rows.Next() {
+		var stat models.SearchStat
+		err := rows.Scan(&stat.Kind, &stat.Category, &stat.Subcategory, &stat.City, &stat.Query,
+			&stat.Searches, &stat.Users, &stat.ZeroResults, &stat.LastAt)
+		if err != nil {
+			log.Printf("Error scanning search stat: %v", err)
+			continue
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// GetActiveUsers counts distinct bot users per day and per month in the
+// range, and the current daily and 30-day active users
+func GetActiveUsers(from, to time.Time) (*models.ActiveUsers, error) {
+	active := &models.ActiveUsers{Daily: make([]models.ActiveCount, 0), Monthly: make([]models.ActiveCount, 0)}
+
+	DB.QueryRow(`SELECT COUNT(DISTINCT telegram_id) FROM bot_events WHERE created_at >= CURRENT_DATE`).Scan(&active.DAU)
+	DB.QueryRow(`SELECT COUNT(DISTINCT telegram_id) FROM bot_events WHERE created_at >= CURRENT_DATE - INTERVAL '29 days'`).Scan(&active.MAU)
+
+	for _, period := range []struct {
+		interval string
+		counts   *[]models.ActiveCount
+	}{{"day", &active.Daily}, {"month", &active.Monthly}} {
+		rows, err := DB.Query(`SELECT date_trunc($1, created_at), COUNT(DISTINCT telegram_id) FROM bot_events
+			WHERE created_at >= $2 AND created_at < $3 GROUP BY 1 ORDER BY 1`, period.interval, from, to)
+		if err != nil {
+			log.Printf("Error loading active users: %v", err)
+			return nil, err
+		}
+		for rows.Next() {
+			var count models.ActiveCount
+			if err := rows.Scan(&count.Date, &count.Users); err != nil {
+				log.Printf("Error scanning active users: %v", err)
+				continue
+			}
+			*period.counts = append(*period.counts, count)
+		}
+		rows.Close()
+	}
+
+	return active, nil
+}
@@ -3,62 +3,178 @@ package database
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"work_kg_backend/internal/models"
 )
 
+// companyVerifiedColumn selects whether the job's company is verified
+const companyVerifiedColumn = `COALESCE((SELECT verified FROM companies WHERE companies.id = company_id), false)`
+
+// employerReviewMatch selects the approved reviews of the job's employer:
+// its company when it has one, otherwise the bot user who posted it
+const employerReviewMatch = `r.status = 'approved' AND (r.employer_company_id = company_id OR (company_id IS NULL AND r.employer_telegram_id = created_by))`
+
+// employerRatingColumns select the employer's average rating and review count
+const employerRatingColumns = `COALESCE((SELECT AVG(rating) FROM employer_reviews r WHERE ` + employerReviewMatch + `), 0),
+	(SELECT COUNT(*) FROM employer_reviews r WHERE ` + employerReviewMatch + `)`
+
 func SaveJob(job *models.Job) error {
-	_, err := DB.Exec(`INSERT INTO jobs (title, description, category, subcategory, city, salary, phone, company, created_by, source)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
-		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.CreatedBy, job.Source)
+	fillJobLocation(job)
+	err := DB.QueryRow(`INSERT INTO jobs (title, description, category, subcategory, city, salary, phone, company, is_active, created_by, source, risk_score, risk_flags, latitude, longitude, photo_file_id, company_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NULLIF($16, ''), $17) RETURNING id, created_at`,
+		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, job.CreatedBy, job.Source,
+		job.RiskScore, pq.Array(job.RiskFlags), job.Latitude, job.Longitude, job.PhotoFileID, job.CompanyID).Scan(&job.ID, &job.CreatedAt)
 	return err
 }
 
 func CreateJob(job *models.Job) error {
-	err := DB.QueryRow(`INSERT INTO jobs (title, description, category, subcategory, city, salary, phone, company, is_active, source)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id, created_at`,
-		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, job.Source).Scan(&job.ID, &job.CreatedAt)
+	fillJobLocation(job)
+	err := DB.QueryRow(`INSERT INTO jobs (title, description, category, subcategory, city, salary, phone, company, is_active, source, risk_score, risk_flags, latitude, longitude, company_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at`,
+		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, job.Source,
+		job.RiskScore, pq.Array(job.RiskFlags), job.Latitude, job.Longitude, job.CompanyID).Scan(&job.ID, &job.CreatedAt)
 	return err
 }
 
 func UpdateJob(id int64, job *models.Job) error {
-	_, err := DB.Exec(`UPDATE jobs SET title=$1, description=$2, category=$3, subcategory=$4, city=$5, salary=$6, phone=$7, company=$8, is_active=$9 WHERE id=$10`,
-		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, id)
+	fillJobLocation(job)
+	_, err := DB.Exec(`UPDATE jobs SET title=$1, description=$2, category=$3, subcategory=$4, city=$5, salary=$6, phone=$7, company=$8, is_active=$9, latitude=$10, longitude=$11, company_id=$12,
+		deactivation_reason = CASE WHEN is_active = $9 THEN deactivation_reason END WHERE id=$13 AND deleted_at IS NULL`,
+		job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, job.Latitude, job.Longitude, job.CompanyID, id)
 	return err
 }
 
+// jobFreshnessColumn is when a job was posted or last extended, which is
+// what the bot sorts listings by
+const jobFreshnessColumn = "COALESCE(bumped_at, created_at)"
+
+// fillJobLocation places a job without exact coordinates at its city center
+func fillJobLocation(job *models.Job) {
+	if job.Latitude != nil && job.Longitude != nil {
+		return
+	}
+	if point, ok := models.CityLocations[job.City]; ok {
+		job.Latitude = &point.Latitude
+		job.Longitude = &point.Longitude
+	}
+}
+
+// DeleteJob moves a job to the trash. It stays there, hidden from listings
+// and the bot, until RestoreJob brings it back or PurgeDeletedJobs removes it.
 func DeleteJob(id int64) error {
-	_, err := DB.Exec(`DELETE FROM jobs WHERE id = $1`, id)
+	_, err := DB.Exec(`UPDATE jobs SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`, id)
 	return err
 }
 
-func GetAllJobs() ([]models.Job, error) {
-	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, is_active, COALESCE(created_by, 0), source, created_at
-		FROM jobs ORDER BY created_at DESC`
+// RestoreJob takes a job out of the trash. It returns false when the job
+// isn't in the trash.
+func RestoreJob(id int64) (bool, error) {
+	result, err := DB.Exec(`UPDATE jobs SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		log.Printf("Error restoring job: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
 
-	rows, err := DB.Query(query)
+// PurgeDeletedJobs permanently removes jobs that were moved to the trash
+// before the given time, together with their applications, reports and posts
+func PurgeDeletedJobs(deletedBefore time.Time) (int64, error) {
+	result, err := DB.Exec(`DELETE FROM jobs WHERE deleted_at IS NOT NULL AND deleted_at < $1`, deletedBefore)
 	if err != nil {
-		return nil, err
+		log.Printf("Error purging deleted jobs: %v", err)
+		return 0, err
 	}
-	defer rows.Close()
+	return result.RowsAffected()
+}
+
+func GetJobByID(id int64) (*models.Job, error) {
+	var job models.Job
+	err := DB.QueryRow(`SELECT id, title, description, category, subcategory, city, salary, phone, company, is_active, COALESCE(created_by, 0), source,
+		COALESCE(photo_file_id, ''), COALESCE(risk_score, 0), COALESCE(risk_flags, '{}'), latitude, longitude, created_at,
+		company_id, `+companyVerifiedColumn+`, `+employerRatingColumns+`, bumped_at,
+		COALESCE(deactivation_reason, '') FROM jobs WHERE id = $1 AND deleted_at IS NULL`, id).Scan(
+		&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company,
+		&job.IsActive, &job.CreatedBy, &job.Source, &job.PhotoFileID, &job.RiskScore, pq.Array(&job.RiskFlags), &job.Latitude, &job.Longitude, &job.CreatedAt,
+		&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews, &job.BumpedAt, &job.DeactivatedBy)
+	return &job, err
+}
 
+func GetAllJobs(filter models.JobFilter) ([]models.Job, error) {
 	jobs := make([]models.Job, 0)
+	err := StreamJobs(filter, func(job models.Job) error {
+		jobs = append(jobs, job)
+		return nil
+	})
+	return jobs, err
+}
+
+// StreamJobs calls fn for each job matching the filter, one row at a time,
+// and stops at the first error fn returns
+func StreamJobs(filter models.JobFilter, fn func(models.Job) error) error {
+	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, is_active, COALESCE(created_by, 0), source,
+		COALESCE(photo_file_id, ''), COALESCE(risk_score, 0), COALESCE(risk_flags, '{}'), latitude, longitude, created_at,
+		company_id, ` + companyVerifiedColumn + `, ` + employerRatingColumns + `, deleted_at, bumped_at
+		FROM jobs WHERE 1=1`
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.Deleted {
+		query += " AND deleted_at IS NOT NULL"
+	} else {
+		query += " AND deleted_at IS NULL"
+	}
+	if filter.MinRisk > 0 {
+		query += fmt.Sprintf(" AND risk_score >= $%d", argNum)
+		args = append(args, filter.MinRisk)
+		argNum++
+	}
+	if filter.ActiveOnly {
+		query += " AND is_active = true"
+	}
+	if !filter.CreatedSince.IsZero() {
+		query += fmt.Sprintf(" AND created_at >= $%d", argNum)
+		args = append(args, filter.CreatedSince)
+		argNum++
+	}
+
+	if filter.Deleted {
+		query += " ORDER BY deleted_at DESC"
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
 	for rows.Next() {
 		var job models.Job
-		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.IsActive, &job.CreatedBy, &job.Source, &job.CreatedAt)
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.IsActive, &job.CreatedBy, &job.Source,
+			&job.PhotoFileID, &job.RiskScore, pq.Array(&job.RiskFlags), &job.Latitude, &job.Longitude, &job.CreatedAt,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews, &job.DeletedAt, &job.BumpedAt)
 		if err != nil {
 			log.Printf("Error scanning job: %v", err)
 			continue
 		}
-		jobs = append(jobs, job)
+		if err := fn(job); err != nil {
+			return err
+		}
 	}
 
-	return jobs, nil
+	return rows.Err()
 }
 
 func SearchJobs(category, subcategory, city string) ([]models.Job, error) {
-	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, created_at
-		FROM jobs WHERE is_active = true`
+	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, COALESCE(photo_file_id, ''), created_at,
+		company_id, ` + companyVerifiedColumn + `, ` + employerRatingColumns + `
+		FROM jobs WHERE is_active = true AND deleted_at IS NULL`
 	args := []interface{}{}
 	argNum := 1
 
@@ -78,7 +194,89 @@ func SearchJobs(category, subcategory, city string) ([]models.Job, error) {
 		argNum++
 	}
 
-	query += " ORDER BY created_at DESC LIMIT 10"
+	query += " ORDER BY " + jobFreshnessColumn + " DESC LIMIT 10"
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var job models.Job
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.PhotoFileID, &job.CreatedAt,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// SearchJobsNear finds active jobs within radiusKm of the point, nearest first
+func SearchJobsNear(category, subcategory string, point models.GeoPoint, radiusKm float64) ([]models.Job, error) {
+	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, COALESCE(photo_file_id, ''), created_at, distance,
+		company_id, ` + companyVerifiedColumn + `, ` + employerRatingColumns + ` FROM (
+		SELECT *, 6371 * acos(LEAST(1, cos(radians($1)) * cos(radians(latitude)) * cos(radians(longitude) - radians($2))
+			+ sin(radians($1)) * sin(radians(latitude)))) AS distance
+		FROM jobs WHERE is_active = true AND deleted_at IS NULL AND latitude IS NOT NULL AND longitude IS NOT NULL
+	) nearby WHERE distance <= $3`
+	args := []interface{}{point.Latitude, point.Longitude, radiusKm}
+	argNum := 4
+
+	if category != "" {
+		query += fmt.Sprintf(" AND category = $%d", argNum)
+		args = append(args, category)
+		argNum++
+	}
+	if subcategory != "" {
+		query += fmt.Sprintf(" AND subcategory = $%d", argNum)
+		args = append(args, subcategory)
+		argNum++
+	}
+
+	query += " ORDER BY distance, " + jobFreshnessColumn + " DESC LIMIT 10"
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var job models.Job
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.PhotoFileID, &job.CreatedAt, &job.DistanceKm,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// SearchJobsByText finds active jobs whose title, description or taxonomy match the query
+func SearchJobsByText(text string, limit, offset int) ([]models.Job, error) {
+	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, created_at,
+		company_id, ` + companyVerifiedColumn + `, ` + employerRatingColumns + `
+		FROM jobs WHERE is_active = true AND deleted_at IS NULL`
+	args := []interface{}{}
+	argNum := 1
+
+	for _, word := range strings.Fields(text) {
+		query += fmt.Sprintf(" AND (title ILIKE $%d OR description ILIKE $%d OR category ILIKE $%d OR subcategory ILIKE $%d OR city ILIKE $%d)",
+			argNum, argNum, argNum, argNum, argNum)
+		args = append(args, containsPattern(word))
+		argNum++
+	}
+
+	query += fmt.Sprintf(" ORDER BY %s DESC LIMIT $%d OFFSET $%d", jobFreshnessColumn, argNum, argNum+1)
+	args = append(args, limit, offset)
 
 	rows, err := DB.Query(query, args...)
 	if err != nil {
@@ -89,7 +287,8 @@ func SearchJobs(category, subcategory, city string) ([]models.Job, error) {
 	var jobs []models.Job
 	for rows.Next() {
 		var job models.Job
-		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.CreatedAt)
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.CreatedAt,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews)
 		if err != nil {
 			continue
 		}
@@ -98,3 +297,150 @@ func SearchJobs(category, subcategory, city string) ([]models.Job, error) {
 
 	return jobs, nil
 }
+
+// CountJobsByCreatorSince counts jobs a bot user posted after the given time
+func CountJobsByCreatorSince(createdBy int64, since time.Time) int {
+	var count int
+	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE created_by = $1 AND created_at >= $2`, createdBy, since).Scan(&count)
+	return count
+}
+
+// phoneDigitsColumn is the job phone reduced to its 9-digit local number
+const phoneDigitsColumn = `RIGHT(regexp_replace(COALESCE(phone, ''), '\D', '', 'g'), 9)`
+
+// GetDuplicateCandidates returns recent jobs that share the phone, author,
+// title or subcategory with a new job and so may be duplicates of it.
+// Phones are compared by their last 9 digits, like moderation does, and the
+// subcategory catches reposts from other accounts with reworded titles.
+func GetDuplicateCandidates(job *models.Job, since time.Time) ([]models.Job, error) {
+	rows, err := DB.Query(`SELECT id, title, description, phone, COALESCE(created_by, 0)
+		FROM jobs WHERE created_at >= $1 AND deleted_at IS NULL
+		AND ((`+phoneDigitsColumn+` <> '' AND `+phoneDigitsColumn+` = RIGHT(regexp_replace($2, '\D', '', 'g'), 9))
+			OR (created_by <> 0 AND created_by = $3) OR LOWER(title) = LOWER($4)
+			OR (category = $5 AND subcategory = $6))
+		ORDER BY created_at DESC LIMIT 200`,
+		since, job.Phone, job.CreatedBy, job.Title, job.Category, job.Subcategory)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var candidate models.Job
+		if err := rows.Scan(&candidate.ID, &candidate.Title, &candidate.Description, &candidate.Phone, &candidate.CreatedBy); err != nil {
+			continue
+		}
+		jobs = append(jobs, candidate)
+	}
+
+	return jobs, nil
+}
+
+// ImportJobs saves imported jobs in one transaction, so either all of them
+// are added or none
+func ImportJobs(jobs []*models.Job) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO jobs (title, description, category, subcategory, city, salary, phone, company, is_active, source, risk_score, risk_flags, latitude, longitude, company_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) RETURNING id, created_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, job := range jobs {
+		fillJobLocation(job)
+		err := stmt.QueryRow(job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone, job.Company, job.IsActive, job.Source,
+			job.RiskScore, pq.Array(job.RiskFlags), job.Latitude, job.Longitude, job.CompanyID).Scan(&job.ID, &job.CreatedAt)
+		if err != nil {
+			log.Printf("Error importing job %q: %v", job.Title, err)
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetPublicJobs returns a page of active jobs for the public API, newest
+// first, together with the number of jobs matching the filter
+func GetPublicJobs(filter models.PublicJobFilter) ([]models.Job, int, error) {
+	where := ` FROM jobs WHERE is_active = true AND deleted_at IS NULL`
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.Category != "" {
+		where += fmt.Sprintf(" AND category = $%d", argNum)
+		args = append(args, filter.Category)
+		argNum++
+	}
+	if filter.Subcategory != "" {
+		where += fmt.Sprintf(" AND subcategory = $%d", argNum)
+		args = append(args, filter.Subcategory)
+		argNum++
+	}
+	if filter.City != "" {
+		where += fmt.Sprintf(" AND city = $%d", argNum)
+		args = append(args, filter.City)
+		argNum++
+	}
+	if filter.CompanyID != 0 {
+		where += fmt.Sprintf(" AND company_id = $%d", argNum)
+		args = append(args, filter.CompanyID)
+		argNum++
+	}
+	if !filter.CreatedSince.IsZero() {
+		where += fmt.Sprintf(" AND created_at >= $%d", argNum)
+		args = append(args, filter.CreatedSince)
+		argNum++
+	}
+	for _, word := range strings.Fields(filter.Query) {
+		where += fmt.Sprintf(" AND (title ILIKE $%d OR description ILIKE $%d)", argNum, argNum)
+		args = append(args, containsPattern(word))
+		argNum++
+	}
+
+	// Counted separately so the total stays right on a page past the end
+	var total int
+	if err := DB.QueryRow(`SELECT COUNT(*)`+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT id, title, description, category, subcategory, city, salary, phone, company, latitude, longitude, created_at,
+		company_id, ` + companyVerifiedColumn + `, ` + employerRatingColumns + where +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argNum, argNum+1)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.Job, 0)
+	for rows.Next() {
+		var job models.Job
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company,
+			&job.Latitude, &job.Longitude, &job.CreatedAt,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews)
+		if err != nil {
+			log.Printf("Error scanning public job: %v", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, total, rows.Err()
+}
+
+// likeEscaper escapes the characters ILIKE treats as wildcards
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern is an ILIKE pattern matching text anywhere, taken literally
+func containsPattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
@@ -0,0 +1,24 @@
+package database
+
+import "log"
+
+// SaveMatchNotification records that the user was told about a matching job.
+// It returns false when they had already been notified about it.
+func SaveMatchNotification(jobID, telegramID int64, score int) (bool, error) {
+	result, err := DB.Exec(`INSERT INTO match_notifications (job_id, telegram_id, score) VALUES ($1, $2, $3)
+		ON CONFLICT (job_id, telegram_id) DO NOTHING`, jobID, telegramID, score)
+	if err != nil {
+		log.Printf("Error saving match notification: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+func SetMatchNotifications(telegramID int64, enabled bool) error {
+	_, err := DB.Exec(`UPDATE resumes SET notify_matches = $1 WHERE telegram_id = $2`, enabled, telegramID)
+	if err != nil {
+		log.Printf("Error updating match notifications: %v", err)
+	}
+	return err
+}
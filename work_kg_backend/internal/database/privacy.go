@@ -0,0 +1,105 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+func SetResumeVisibility(telegramID int64, visibility string) error {
+	_, err := DB.Exec(`UPDATE resumes SET visibility = $1 WHERE telegram_id = $2`, visibility, telegramID)
+	if err != nil {
+		log.Printf("Error updating resume visibility: %v", err)
+	}
+	return err
+}
+
+func SetResumeAnonymous(telegramID int64, anonymous bool) error {
+	_, err := DB.Exec(`UPDATE resumes SET anonymous = $1 WHERE telegram_id = $2`, anonymous, telegramID)
+	if err != nil {
+		log.Printf("Error updating resume anonymity: %v", err)
+	}
+	return err
+}
+
+// CreateContactRequest asks for a resume's contacts on behalf of an admin,
+// reusing the admin's pending request if there is one
+func CreateContactRequest(resumeID, adminID int64) (*models.ContactRequest, error) {
+	request := models.ContactRequest{ResumeID: resumeID, AdminID: adminID}
+
+	err := DB.QueryRow(`SELECT id, status, created_at FROM contact_requests
+		WHERE resume_id = $1 AND admin_id = $2 AND status = 'pending'`, resumeID, adminID).Scan(
+		&request.ID, &request.Status, &request.CreatedAt)
+	if err == nil {
+		return &request, nil
+	}
+
+	err = DB.QueryRow(`INSERT INTO contact_requests (resume_id, admin_id) VALUES ($1, $2)
+		RETURNING id, status, created_at`, resumeID, adminID).Scan(&request.ID, &request.Status, &request.CreatedAt)
+	return &request, err
+}
+
+func GetContactRequest(id int64) (*models.ContactRequest, error) {
+	var request models.ContactRequest
+	err := DB.QueryRow(`SELECT id, resume_id, admin_id, status, created_at, responded_at
+		FROM contact_requests WHERE id = $1`, id).Scan(
+		&request.ID, &request.ResumeID, &request.AdminID, &request.Status, &request.CreatedAt, &request.RespondedAt)
+	return &request, err
+}
+
+// RespondToContactRequest records the seeker's answer to a pending request.
+// It returns false when the request was already answered.
+func RespondToContactRequest(id int64, approved bool) (bool, error) {
+	status := "declined"
+	if approved {
+		status = "approved"
+	}
+
+	result, err := DB.Exec(`UPDATE contact_requests SET status = $1, responded_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = 'pending'`, status, id)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+func HasApprovedContactRequest(resumeID, adminID int64) bool {
+	var exists bool
+	DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM contact_requests
+		WHERE resume_id = $1 AND admin_id = $2 AND status = 'approved')`, resumeID, adminID).Scan(&exists)
+	return exists
+}
+
+func LogContactView(resumeID, adminID int64, ipAddress string) error {
+	_, err := DB.Exec(`INSERT INTO contact_views (resume_id, admin_id, ip_address) VALUES ($1, $2, $3)`,
+		resumeID, adminID, ipAddress)
+	if err != nil {
+		log.Printf("Error logging contact view: %v", err)
+	}
+	return err
+}
+
+func GetContactViews(resumeID int64) ([]models.ContactView, error) {
+	rows, err := DB.Query(`SELECT v.id, v.resume_id, COALESCE(v.admin_id, 0), COALESCE(a.email, ''),
+		COALESCE(v.ip_address, ''), v.viewed_at
+		FROM contact_views v LEFT JOIN admin_users a ON a.id = v.admin_id
+		WHERE v.resume_id = $1 ORDER BY v.viewed_at DESC`, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	views := make([]models.ContactView, 0)
+	for rows.Next() {
+		var view models.ContactView
+		err := rows.Scan(&view.ID, &view.ResumeID, &view.AdminID, &view.AdminEmail, &view.IPAddress, &view.ViewedAt)
+		if err != nil {
+			log.Printf("Error scanning contact view: %v", err)
+			continue
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+// SaveJobReport stores a user's report on a job. Each user can report a job
+// once; it returns false when the report already existed.
+func SaveJobReport(jobID, telegramID int64, reason string) (bool, error) {
+	result, err := DB.Exec(`INSERT INTO job_reports (job_id, telegram_id, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (job_id, telegram_id) DO NOTHING`, jobID, telegramID, reason)
+	if err != nil {
+		log.Printf("Error saving job report: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// CountPendingReports counts distinct users with an unreviewed report on the job
+func CountPendingReports(jobID int64) int {
+	var count int
+	DB.QueryRow(`SELECT COUNT(DISTINCT telegram_id) FROM job_reports WHERE job_id = $1 AND status = 'pending'`, jobID).Scan(&count)
+	return count
+}
+
+func SetJobActive(jobID int64, active bool) error {
+	_, err := DB.Exec(`UPDATE jobs SET is_active = $1, deactivation_reason = NULL WHERE id = $2`, active, jobID)
+	return err
+}
+
+// DeactivateReportedJob hides an active job and records that reports, not
+// an admin, took it down. It returns false when the job wasn't active.
+func DeactivateReportedJob(jobID int64) (bool, error) {
+	result, err := DB.Exec(`UPDATE jobs SET is_active = false, deactivation_reason = $1
+		WHERE id = $2 AND is_active = true AND deleted_at IS NULL`, models.DeactivatedByReports, jobID)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
+
+// GetReportedJobs lists jobs with at least one report, most pending reports first
+func GetReportedJobs() ([]models.ReportedJob, error) {
+	rows, err := DB.Query(`SELECT j.id, j.title, j.description, j.category, j.subcategory, j.city, j.salary, j.phone, j.company,
+		j.is_active, COALESCE(j.created_by, 0), j.source, j.created_at,
+		COUNT(*) FILTER (WHERE r.status = 'pending'), COUNT(*), MAX(r.created_at),
+		COALESCE(j.deactivation_reason, '')
+		FROM job_reports r JOIN jobs j ON j.id = r.job_id
+		WHERE j.deleted_at IS NULL
+		GROUP BY j.id
+		ORDER BY 14 DESC, 16 DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := make([]models.ReportedJob, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		var job models.ReportedJob
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company,
+			&job.IsActive, &job.CreatedBy, &job.Source, &job.CreatedAt,
+			&job.PendingReports, &job.TotalReports, &job.LastReportedAt, &job.DeactivatedBy)
+		if err != nil {
+			log.Printf("Error scanning reported job: %v", err)
+			continue
+		}
+		job.Reasons = make(map[string]int)
+		index[job.ID] = len(jobs)
+		jobs = append(jobs, job)
+	}
+
+	reasonRows, err := DB.Query(`SELECT job_id, reason, COUNT(*) FROM job_reports GROUP BY job_id, reason`)
+	if err != nil {
+		return nil, err
+	}
+	defer reasonRows.Close()
+
+	for reasonRows.Next() {
+		var jobID int64
+		var reason string
+		var count int
+		if err := reasonRows.Scan(&jobID, &reason, &count); err != nil {
+			continue
+		}
+		if i, ok := index[jobID]; ok {
+			jobs[i].Reasons[reason] = count
+		}
+	}
+
+	return jobs, nil
+}
+
+// ReviewJobReports closes all pending reports on a job with the given status
+func ReviewJobReports(jobID int64, status string) (int64, error) {
+	result, err := DB.Exec(`UPDATE job_reports SET status = $1 WHERE job_id = $2 AND status = 'pending'`, status, jobID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
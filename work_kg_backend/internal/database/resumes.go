@@ -1,14 +1,25 @@
 package database
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 
+	"github.com/lib/pq"
 	"work_kg_backend/internal/models"
 )
 
-func SaveResume(telegramID int64, username, name, phone, city, specialty, experience string) error {
-	_, err := DB.Exec(`INSERT INTO resumes (telegram_id, username, name, phone, city, specialty, experience)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+// SaveResume creates or replaces the user's resume together with its work history
+func SaveResume(resume *models.Resume) error {
+	tx, err := DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`INSERT INTO resumes (telegram_id, username, name, phone, city, specialty, experience,
+		education, skills, desired_salary, ready_to_relocate, schedule, photo_file_id, cv_file_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		ON CONFLICT (telegram_id) DO UPDATE SET
 		username = EXCLUDED.username,
 		name = EXCLUDED.name,
@@ -16,49 +27,150 @@ func SaveResume(telegramID int64, username, name, phone, city, specialty, experi
 		city = EXCLUDED.city,
 		specialty = EXCLUDED.specialty,
 		experience = EXCLUDED.experience,
-		updated_at = CURRENT_TIMESTAMP`,
-		telegramID, username, name, phone, city, specialty, experience)
+		education = EXCLUDED.education,
+		skills = EXCLUDED.skills,
+		desired_salary = EXCLUDED.desired_salary,
+		ready_to_relocate = EXCLUDED.ready_to_relocate,
+		schedule = EXCLUDED.schedule,
+		photo_file_id = EXCLUDED.photo_file_id,
+		cv_file_id = EXCLUDED.cv_file_id,
+		updated_at = CURRENT_TIMESTAMP
+		RETURNING id`,
+		resume.TelegramID, resume.Username, resume.Name, resume.Phone, resume.City, resume.Specialty, resume.Experience,
+		resume.Education, pq.Array(resume.Skills), resume.DesiredSalary, resume.ReadyToRelocate, resume.Schedule,
+		resume.PhotoFileID, resume.CVFileID).Scan(&resume.ID)
 	if err != nil {
 		log.Printf("Error saving resume: %v", err)
+		return err
 	}
-	return err
+
+	if _, err := tx.Exec(`DELETE FROM resume_work_history WHERE resume_id = $1`, resume.ID); err != nil {
+		log.Printf("Error clearing work history: %v", err)
+		return err
+	}
+	for i, work := range resume.WorkHistory {
+		_, err := tx.Exec(`INSERT INTO resume_work_history (resume_id, employer, position, period, sort_order)
+			VALUES ($1, $2, $3, $4, $5)`, resume.ID, work.Employer, work.Position, work.Period, i)
+		if err != nil {
+			log.Printf("Error saving work history: %v", err)
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func UpdateUserFormData(telegramID int64, name, phone, city, specialty, experience string) error {
+func UpdateUserFormData(telegramID int64, name, phone, city, specialty, experience string, phoneVerified bool) error {
 	_, err := DB.Exec(`UPDATE users SET
 		first_name = COALESCE(NULLIF($1, ''), first_name),
 		phone = $2,
 		city = $3,
 		specialty = $4,
-		experience = $5
-		WHERE telegram_id = $6`,
-		name, phone, city, specialty, experience, telegramID)
+		experience = $5,
+		phone_verified = $6
+		WHERE telegram_id = $7`,
+		name, phone, city, specialty, experience, phoneVerified, telegramID)
 	if err != nil {
 		log.Printf("Error saving form data to users: %v", err)
 	}
 	return err
 }
 
-func GetAllResumes() ([]models.Resume, error) {
-	rows, err := DB.Query(`SELECT id, telegram_id, COALESCE(username, ''), COALESCE(name, ''),
+// GetAllResumes lists every resume that isn't hidden by its owner
+func GetAllResumes(filter models.ResumeFilter) ([]models.Resume, error) {
+	var resumes []models.Resume
+	err := StreamResumes(filter, func(resume models.Resume) error {
+		resumes = append(resumes, resume)
+		return nil
+	})
+	return resumes, err
+}
+
+// workHistoryColumn aggregates a resume's work history into a JSON array
+const workHistoryColumn = `COALESCE((SELECT json_agg(json_build_object('employer', COALESCE(w.employer, ''),
+	'position', COALESCE(w.position, ''), 'period', COALESCE(w.period, '')) ORDER BY w.sort_order)
+	FROM resume_work_history w WHERE w.resume_id = resumes.id), '[]')`
+
+// StreamResumes calls fn for each resume GetAllResumes would list, one row
+// at a time, work history included
+func StreamResumes(filter models.ResumeFilter, fn func(models.Resume) error) error {
+	query := `SELECT id, telegram_id, COALESCE(username, ''), COALESCE(name, ''),
 		COALESCE(phone, ''), COALESCE(city, ''), COALESCE(specialty, ''), COALESCE(experience, ''),
-		created_at, updated_at FROM resumes ORDER BY updated_at DESC`)
+		COALESCE(education, ''), COALESCE(skills, '{}'), COALESCE(desired_salary, ''), COALESCE(ready_to_relocate, false),
+		COALESCE(schedule, ''), COALESCE(photo_file_id, ''), COALESCE(cv_file_id, ''),
+		COALESCE(visibility, 'public'), COALESCE(anonymous, false), COALESCE(notify_matches, true),
+		` + candidateColumns("resumes") + `, ` + workHistoryColumn + `,
+		created_at, updated_at FROM resumes WHERE COALESCE(visibility, 'public') <> 'hidden'`
+	args := []interface{}{}
+	argNum := 1
+
+	if filter.Tag != "" {
+		query += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM candidate_tags t WHERE t.telegram_id = resumes.telegram_id AND t.tag = $%d)", argNum)
+		args = append(args, filter.Tag)
+		argNum++
+	}
+	if filter.Status != "" {
+		query += fmt.Sprintf(" AND COALESCE((SELECT status FROM candidates c WHERE c.telegram_id = resumes.telegram_id), 'new') = $%d", argNum)
+		args = append(args, filter.Status)
+		argNum++
+	}
+
+	query += " ORDER BY updated_at DESC"
+
+	rows, err := DB.Query(query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var resumes []models.Resume
 	for rows.Next() {
 		var resume models.Resume
+		var workHistory []byte
 		err := rows.Scan(&resume.ID, &resume.TelegramID, &resume.Username, &resume.Name, &resume.Phone,
-			&resume.City, &resume.Specialty, &resume.Experience, &resume.CreatedAt, &resume.UpdatedAt)
+			&resume.City, &resume.Specialty, &resume.Experience,
+			&resume.Education, pq.Array(&resume.Skills), &resume.DesiredSalary, &resume.ReadyToRelocate,
+			&resume.Schedule, &resume.PhotoFileID, &resume.CVFileID,
+			&resume.Visibility, &resume.Anonymous, &resume.NotifyMatches,
+			&resume.PipelineStatus, pq.Array(&resume.Tags), &workHistory, &resume.CreatedAt, &resume.UpdatedAt)
 		if err != nil {
 			log.Printf("Error scanning resume: %v", err)
 			continue
 		}
-		resumes = append(resumes, resume)
+		resume.WorkHistory = make([]models.WorkExperience, 0)
+		if err := json.Unmarshal(workHistory, &resume.WorkHistory); err != nil {
+			log.Printf("Error decoding work history: %v", err)
+		}
+		if err := fn(resume); err != nil {
+			return err
+		}
 	}
 
-	return resumes, nil
+	return rows.Err()
+}
+
+// GetResumeByTelegramID returns the user's resume without work history
+func GetResumeByTelegramID(telegramID int64) (*models.Resume, error) {
+	return getResume(`telegram_id = $1`, telegramID)
+}
+
+// GetResumeByID returns a resume without work history
+func GetResumeByID(id int64) (*models.Resume, error) {
+	return getResume(`id = $1`, id)
+}
+
+func getResume(where string, arg interface{}) (*models.Resume, error) {
+	var resume models.Resume
+	err := DB.QueryRow(`SELECT id, telegram_id, COALESCE(username, ''), COALESCE(name, ''), COALESCE(phone, ''),
+		COALESCE(city, ''), COALESCE(specialty, ''), COALESCE(experience, ''), COALESCE(education, ''),
+		COALESCE(skills, '{}'), COALESCE(desired_salary, ''), COALESCE(ready_to_relocate, false), COALESCE(schedule, ''),
+		COALESCE(photo_file_id, ''), COALESCE(cv_file_id, ''),
+		COALESCE(visibility, 'public'), COALESCE(anonymous, false), COALESCE(notify_matches, true),
+		`+candidateColumns("resumes")+`, created_at, updated_at FROM resumes WHERE `+where, arg).Scan(
+		&resume.ID, &resume.TelegramID, &resume.Username, &resume.Name, &resume.Phone,
+		&resume.City, &resume.Specialty, &resume.Experience, &resume.Education,
+		pq.Array(&resume.Skills), &resume.DesiredSalary, &resume.ReadyToRelocate, &resume.Schedule,
+		&resume.PhotoFileID, &resume.CVFileID,
+		&resume.Visibility, &resume.Anonymous, &resume.NotifyMatches,
+		&resume.PipelineStatus, pq.Array(&resume.Tags), &resume.CreatedAt, &resume.UpdatedAt)
+	return &resume, err
 }
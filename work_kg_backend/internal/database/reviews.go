@@ -0,0 +1,100 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"work_kg_backend/internal/models"
+)
+
+func SaveEmployerReview(review *models.EmployerReview) error {
+	err := DB.QueryRow(`INSERT INTO employer_reviews (job_id, employer_company_id, employer_telegram_id, telegram_id, rating, comment, status)
+		VALUES ($1, $2, NULLIF($3, 0), $4, $5, $6, $7) RETURNING id, created_at`,
+		review.JobID, review.CompanyID, review.EmployerTelegramID, review.TelegramID, review.Rating, review.Comment, review.Status,
+	).Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		log.Printf("Error saving employer review: %v", err)
+	}
+	return err
+}
+
+// employerCondition matches reviews of the employer: the company when set,
+// otherwise the bot user
+func employerCondition(companyID *int64, employerTelegramID int64, argNum int) (string, interface{}) {
+	if companyID != nil {
+		return fmt.Sprintf("employer_company_id = $%d", argNum), *companyID
+	}
+	return fmt.Sprintf("employer_company_id IS NULL AND employer_telegram_id = $%d", argNum), employerTelegramID
+}
+
+// HasReviewedEmployer reports whether the user already reviewed this employer, for any job
+func HasReviewedEmployer(telegramID int64, companyID *int64, employerTelegramID int64) bool {
+	condition, arg := employerCondition(companyID, employerTelegramID, 2)
+	var exists bool
+	DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM employer_reviews WHERE telegram_id = $1 AND `+condition+`)`,
+		telegramID, arg).Scan(&exists)
+	return exists
+}
+
+// CountReviewsByUserSince counts reviews the user wrote after the given time
+func CountReviewsByUserSince(telegramID int64, since time.Time) int {
+	var count int
+	DB.QueryRow(`SELECT COUNT(*) FROM employer_reviews WHERE telegram_id = $1 AND created_at >= $2`,
+		telegramID, since).Scan(&count)
+	return count
+}
+
+// CountEmployerReviewsSince counts reviews the employer received after the given time
+func CountEmployerReviewsSince(companyID *int64, employerTelegramID int64, since time.Time) int {
+	condition, arg := employerCondition(companyID, employerTelegramID, 2)
+	var count int
+	DB.QueryRow(`SELECT COUNT(*) FROM employer_reviews WHERE created_at >= $1 AND `+condition,
+		since, arg).Scan(&count)
+	return count
+}
+
+// GetEmployerReviews lists reviews for moderation, optionally by status
+func GetEmployerReviews(status string) ([]models.EmployerReview, error) {
+	query := `SELECT r.id, COALESCE(r.job_id, 0), COALESCE(j.title, ''), r.employer_company_id, COALESCE(r.employer_telegram_id, 0),
+		r.telegram_id, r.rating, COALESCE(r.comment, ''), r.status, r.created_at, r.moderated_at
+		FROM employer_reviews r LEFT JOIN jobs j ON j.id = r.job_id`
+	args := []interface{}{}
+	if status != "" {
+		query += " WHERE r.status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY r.created_at DESC"
+
+	rows, err := DB.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := make([]models.EmployerReview, 0)
+	for rows.Next() {
+		var review models.EmployerReview
+		err := rows.Scan(&review.ID, &review.JobID, &review.JobTitle, &review.CompanyID, &review.EmployerTelegramID,
+			&review.TelegramID, &review.Rating, &review.Comment, &review.Status, &review.CreatedAt, &review.ModeratedAt)
+		if err != nil {
+			log.Printf("Error scanning employer review: %v", err)
+			continue
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+// ModerateEmployerReview approves or rejects a review. It returns false
+// when the review doesn't exist.
+func ModerateEmployerReview(id int64, status string) (bool, error) {
+	result, err := DB.Exec(`UPDATE employer_reviews SET status = $1, moderated_at = CURRENT_TIMESTAMP WHERE id = $2`, status, id)
+	if err != nil {
+		log.Printf("Error moderating employer review: %v", err)
+		return false, err
+	}
+	affected, _ := result.RowsAffected()
+	return affected > 0, nil
+}
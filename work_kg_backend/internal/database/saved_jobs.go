@@ -0,0 +1,71 @@
+package database
+
+import (
+	"log"
+
+	"work_kg_backend/internal/models"
+)
+
+func SaveFavoriteJob(telegramID, jobID int64) error {
+	_, err := DB.Exec(`INSERT INTO saved_jobs (telegram_id, job_id) VALUES ($1, $2)
+		ON CONFLICT (telegram_id, job_id) DO NOTHING`, telegramID, jobID)
+	if err != nil {
+		log.Printf("Error saving favorite job: %v", err)
+	}
+	return err
+}
+
+func RemoveFavoriteJob(telegramID, jobID int64) error {
+	_, err := DB.Exec(`DELETE FROM saved_jobs WHERE telegram_id = $1 AND job_id = $2`, telegramID, jobID)
+	if err != nil {
+		log.Printf("Error removing favorite job: %v", err)
+	}
+	return err
+}
+
+// GetSavedJobIDs returns the set of job IDs the user has saved
+func GetSavedJobIDs(telegramID int64) map[int64]bool {
+	saved := make(map[int64]bool)
+
+	rows, err := DB.Query(`SELECT job_id FROM saved_jobs WHERE telegram_id = $1`, telegramID)
+	if err != nil {
+		return saved
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var jobID int64
+		if err := rows.Scan(&jobID); err == nil {
+			saved[jobID] = true
+		}
+	}
+
+	return saved
+}
+
+// GetSavedJobs returns the user's saved jobs, including ones closed or
+// deleted since saving, which are reported as inactive
+func GetSavedJobs(telegramID int64) ([]models.Job, error) {
+	rows, err := DB.Query(`SELECT j.id, j.title, j.description, j.category, j.subcategory, j.city, j.salary, j.phone, j.company, COALESCE(j.photo_file_id, ''), j.is_active AND j.deleted_at IS NULL, j.created_at,
+		j.company_id, COALESCE((SELECT verified FROM companies c WHERE c.id = j.company_id), false), `+employerRatingColumns+`
+		FROM saved_jobs s JOIN jobs j ON j.id = s.job_id
+		WHERE s.telegram_id = $1 ORDER BY s.created_at DESC`, telegramID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var job models.Job
+		err := rows.Scan(&job.ID, &job.Title, &job.Description, &job.Category, &job.Subcategory, &job.City, &job.Salary, &job.Phone, &job.Company, &job.PhotoFileID, &job.IsActive, &job.CreatedAt,
+			&job.CompanyID, &job.CompanyVerified, &job.EmployerRating, &job.EmployerReviews)
+		if err != nil {
+			log.Printf("Error scanning saved job: %v", err)
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
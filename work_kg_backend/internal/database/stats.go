@@ -1,17 +1,72 @@
 package database
 
-import "work_kg_backend/internal/models"
+import (
+	"fmt"
+	"log"
+
+	"work_kg_backend/internal/models"
+)
 
 func GetStats() models.Stats {
 	var stats models.Stats
 
-	DB.QueryRow(`SELECT COUNT(*) FROM jobs`).Scan(&stats.TotalJobs)
-	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE is_active = true`).Scan(&stats.ActiveJobs)
+	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE deleted_at IS NULL`).Scan(&stats.TotalJobs)
+	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE is_active = true AND deleted_at IS NULL`).Scan(&stats.ActiveJobs)
 	DB.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&stats.TotalUsers)
 	DB.QueryRow(`SELECT COUNT(*) FROM resumes`).Scan(&stats.TotalResumes)
-	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE DATE(created_at) = CURRENT_DATE`).Scan(&stats.TodayJobs)
+	DB.QueryRow(`SELECT COUNT(*) FROM jobs WHERE DATE(created_at) = CURRENT_DATE AND deleted_at IS NULL`).Scan(&stats.TodayJobs)
 	DB.QueryRow(`SELECT COUNT(*) FROM users WHERE DATE(created_at) = CURRENT_DATE`).Scan(&stats.TodayUsers)
 	DB.QueryRow(`SELECT COUNT(*) FROM resumes WHERE DATE(created_at) = CURRENT_DATE`).Scan(&stats.TodayResumes)
 
 	return stats
 }
+
+// timeSeriesGroups are the columns each metric is broken down by; empty
+// when the metric has no such field
+var timeSeriesGroups = map[string]map[string]string{
+	models.GroupByCategory: {"jobs": "category", "users": "''", "resumes": "''", "applications": "j.category"},
+	models.GroupByCity:     {"jobs": "city", "users": "city", "resumes": "city", "applications": "j.city"},
+	models.GroupBySource:   {"jobs": "source", "users": "''", "resumes": "''", "applications": "j.source"},
+	"":                     {"jobs": "''", "users": "''", "resumes": "''", "applications": "''"},
+}
+
+// GetTimeSeries counts new jobs, users, resumes and applications per bucket
+// in one pass over the four tables
+func GetTimeSeries(filter models.TimeSeriesFilter) ([]models.TimeSeriesCount, error) {
+	groups, ok := timeSeriesGroups[filter.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown group %q", filter.GroupBy)
+	}
+
+	rows, err := DB.Query(`SELECT metric, COALESCE(grp, ''), date_trunc($1, created_at) AS bucket, COUNT(*) FROM (
+			SELECT 'jobs' AS metric, `+groups["jobs"]+` AS grp, created_at FROM jobs
+			WHERE created_at >= $2 AND created_at < $3 AND deleted_at IS NULL
+			UNION ALL
+			SELECT 'users', `+groups["users"]+`, created_at FROM users
+			WHERE created_at >= $2 AND created_at < $3
+			UNION ALL
+			SELECT 'resumes', `+groups["resumes"]+`, created_at FROM resumes
+			WHERE created_at >= $2 AND created_at < $3
+			UNION ALL
+			SELECT 'applications', `+groups["applications"]+`, a.created_at FROM applications a JOIN jobs j ON j.id = a.job_id
+			WHERE a.created_at >= $2 AND a.created_at < $3
+		) events
+		GROUP BY 1, 2, 3 ORDER BY 3, 1, 2`, filter.Interval, filter.From, filter.To)
+	if err != nil {
+		log.Printf("Error loading time series: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []models.TimeSeriesCount
+	for rows.Next() {
+		var count models.TimeSeriesCount
+		if err := rows.Scan(&count.Metric, &count.Group, &count.Bucket, &count.Count); err != nil {
+			log.Printf("Error scanning time series: %v", err)
+			continue
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"log"
 
+	"github.com/lib/pq"
 	"work_kg_backend/internal/models"
 )
 
@@ -26,10 +27,10 @@ func GetUserByTelegramID(telegramID int64) (*models.User, error) {
 	var specialty, experience sql.NullString
 
 	err := DB.QueryRow(`SELECT id, telegram_id, COALESCE(username, ''), COALESCE(first_name, ''),
-		COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(city, ''), specialty, experience, role, created_at
+		COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(phone_verified, false), company_id, COALESCE(city, ''), specialty, experience, role, created_at
 		FROM users WHERE telegram_id = $1`, telegramID).Scan(
 		&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName,
-		&user.Phone, &user.City, &specialty, &experience, &user.Role, &user.CreatedAt)
+		&user.Phone, &user.PhoneVerified, &user.CompanyID, &user.City, &specialty, &experience, &user.Role, &user.CreatedAt)
 
 	if specialty.Valid {
 		user.Specialty = specialty.String
@@ -42,27 +43,46 @@ func GetUserByTelegramID(telegramID int64) (*models.User, error) {
 }
 
 func GetAllUsers() ([]models.User, error) {
+	var users []models.User
+	err := StreamUsers(func(user models.User) error {
+		users = append(users, user)
+		return nil
+	})
+	return users, err
+}
+
+// resumePrivacyColumns selects the visibility and anonymity of the user's
+// resume, whose form data is copied into users
+const resumePrivacyColumns = `COALESCE((SELECT visibility FROM resumes WHERE resumes.telegram_id = users.telegram_id), 'public'),
+		COALESCE((SELECT anonymous FROM resumes WHERE resumes.telegram_id = users.telegram_id), false)`
+
+// StreamUsers calls fn for each user, newest first, one row at a time
+func StreamUsers(fn func(models.User) error) error {
 	rows, err := DB.Query(`SELECT id, telegram_id, COALESCE(username, ''), COALESCE(first_name, ''),
-		COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(city, ''), COALESCE(specialty, ''),
-		COALESCE(experience, ''), role, created_at FROM users ORDER BY created_at DESC`)
+		COALESCE(last_name, ''), COALESCE(phone, ''), COALESCE(phone_verified, false), company_id, COALESCE(city, ''),
+		COALESCE(specialty, ''), COALESCE(experience, ''), role, ` + candidateColumns("users") + `, created_at,
+		` + resumePrivacyColumns + ` FROM users ORDER BY created_at DESC`)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var users []models.User
 	for rows.Next() {
 		var user models.User
 		err := rows.Scan(&user.ID, &user.TelegramID, &user.Username, &user.FirstName, &user.LastName,
-			&user.Phone, &user.City, &user.Specialty, &user.Experience, &user.Role, &user.CreatedAt)
+			&user.Phone, &user.PhoneVerified, &user.CompanyID, &user.City, &user.Specialty, &user.Experience, &user.Role,
+			&user.PipelineStatus, pq.Array(&user.Tags), &user.CreatedAt,
+			&user.ResumeVisibility, &user.ResumeAnonymous)
 		if err != nil {
 			log.Printf("Error scanning user: %v", err)
 			continue
 		}
-		users = append(users, user)
+		if err := fn(user); err != nil {
+			return err
+		}
 	}
 
-	return users, nil
+	return rows.Err()
 }
 
 func GetUsernameByTelegramID(telegramID int64) string {
@@ -70,3 +90,12 @@ func GetUsernameByTelegramID(telegramID int64) string {
 	DB.QueryRow(`SELECT COALESCE(username, '') FROM users WHERE telegram_id = $1`, telegramID).Scan(&username)
 	return username
 }
+
+// SaveVerifiedPhone stores a phone number the user shared as their own Telegram contact.
+func SaveVerifiedPhone(telegramID int64, phone string) error {
+	_, err := DB.Exec(`UPDATE users SET phone = $1, phone_verified = true WHERE telegram_id = $2`, phone, telegramID)
+	if err != nil {
+		log.Printf("Error saving verified phone: %v", err)
+	}
+	return err
+}
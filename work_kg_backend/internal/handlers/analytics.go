@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+const (
+	defaultAnalyticsDays = 30
+	defaultSearchLimit   = 50
+	maxSearchLimit       = 500
+)
+
+// HandleGetFunnel shows where users drop out of a bot wizard
+// (?wizard=job|form) between ?from= and ?to=
+func HandleGetFunnel(w http.ResponseWriter, r *http.Request) {
+	wizard := r.URL.Query().Get("wizard")
+	if wizard == "" {
+		wizard = "job"
+	}
+	order, ok := bot.WizardFunnel(wizard)
+	if !ok {
+		http.Error(w, "Wizard must be job or form", http.StatusBadRequest)
+		return
+	}
+
+	from, to, ok := analyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	funnel, steps, err := database.GetFunnel(wizard, from, to)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	funnel.Steps = make([]models.FunnelStep, 0, len(order))
+	for i, name := range order {
+		step := steps[name]
+		step.Step = name
+		if i == 0 {
+			funnel.Started = step.Users
+		} else if previous := funnel.Steps[i-1].Users; previous > 0 && step.Users < previous {
+			step.DropOff = percent(previous-step.Users, previous)
+		}
+		funnel.Steps = append(funnel.Steps, step)
+	}
+	if funnel.Started > 0 {
+		funnel.Conversion = percent(funnel.Completed, funnel.Started)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(funnel)
+}
+
+// HandleGetSearchStats lists the most frequent bot searches; with
+// ?zero_results=true only the ones that found nothing
+func HandleGetSearchStats(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	filter := models.SearchStatFilter{From: from, To: to, Limit: defaultSearchLimit}
+	filter.ZeroResults, _ = strconv.ParseBool(r.URL.Query().Get("zero_results"))
+	if limit, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && limit > 0 {
+		filter.Limit = min(limit, maxSearchLimit)
+	}
+
+	stats, err := database.GetSearchStats(filter)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// HandleGetActiveUsers returns daily and monthly active bot users
+func HandleGetActiveUsers(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := analyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	active, err := database.GetActiveUsers(from, to)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(active)
+}
+
+// analyticsRange reads ?from= and ?to= (dates, both inclusive), defaulting
+// to the last 30 days
+func analyticsRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	from, err := parseTimeParam(r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return from, from, false
+	}
+	to, err := parseTimeParam(r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return from, to, false
+	}
+
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	to = truncateToBucket(to, models.IntervalDay).AddDate(0, 0, 1)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultAnalyticsDays)
+	}
+	if !from.Before(to) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// percent returns part/total as a percentage rounded to one decimal
+func percent(part, total int) float64 {
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// recordAudit logs a change made by the authenticated admin. before and
+// after are the entity's state around the change (nil when it didn't
+// exist); the changed fields are stored alongside them.
+func recordAudit(r *http.Request, action, entityType string, entityID interface{}, before, after interface{}) {
+	entry := models.AuditEntry{
+		AdminEmail: r.Header.Get("X-User-Email"),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		IPAddress:  r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+	}
+	if adminID := currentAdminID(r); adminID != 0 {
+		entry.AdminID = &adminID
+	}
+	if before != nil {
+		entry.Before, _ = json.Marshal(before)
+	}
+	if after != nil {
+		entry.After, _ = json.Marshal(after)
+	}
+	entry.Changes = auditChanges(entry.Before, entry.After)
+
+	database.SaveAuditEntry(&entry)
+}
+
+// auditChanges compares two JSON objects field by field
+func auditChanges(before, after json.RawMessage) map[string]models.AuditChange {
+	var from, to map[string]interface{}
+	json.Unmarshal(before, &from)
+	json.Unmarshal(after, &to)
+
+	changes := make(map[string]models.AuditChange)
+	for field, value := range from {
+		if !reflect.DeepEqual(value, to[field]) {
+			changes[field] = models.AuditChange{From: value, To: to[field]}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = models.AuditChange{From: nil, To: value}
+		}
+	}
+	return changes
+}
+
+// HandleGetAuditLog lists audit entries, newest first. Filters: admin_id,
+// action, entity_type, entity_id, from and to (RFC 3339 or YYYY-MM-DD),
+// limit and offset.
+func HandleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.AuditFilter{
+		Action:     query.Get("action"),
+		EntityType: query.Get("entity_type"),
+		EntityID:   query.Get("entity_id"),
+	}
+	filter.AdminID, _ = strconv.ParseInt(query.Get("admin_id"), 10, 64)
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+	filter.Offset, _ = strconv.Atoi(query.Get("offset"))
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return
+	}
+	if filter.To, err = parseEndParam(query.Get("to")); err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := database.GetAuditLog(filter)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+// parseTimeParam reads an optional RFC 3339 timestamp or YYYY-MM-DD date
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseEndParam reads the exclusive end of a range like parseTimeParam, but
+// a plain date includes that whole day
+func parseEndParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return date, err
+	}
+	return date.AddDate(0, 0, 1), nil
+}
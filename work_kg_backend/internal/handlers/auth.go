@@ -3,12 +3,18 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 
 	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/models"
 )
 
 func HandleLogin(w http.ResponseWriter, r *http.Request) {
+	// Login doesn't pass through AuthMiddleware, so the actor headers are
+	// whatever the client sent; failed attempts must not be attributed to them
+	r.Header.Del("X-User-ID")
+	r.Header.Del("X-User-Email")
+
 	var req models.LoginRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -18,15 +24,22 @@ func HandleLogin(w http.ResponseWriter, r *http.Request) {
 
 	user, err := database.GetAdminByEmail(req.Email)
 	if err != nil {
+		recordAudit(r, "login_failed", "admin", req.Email, nil, nil)
 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
 	if !database.ValidateAdminPassword(user.Password, req.Password) {
+		recordAudit(r, "login_failed", "admin", user.ID, nil, nil)
 		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
+	// Name the actor now that the password checks out
+	r.Header.Set("X-User-ID", strconv.FormatInt(user.ID, 10))
+	r.Header.Set("X-User-Email", user.Email)
+	recordAudit(r, "login", "admin", user.ID, nil, nil)
+
 	// Return email as token (simple auth - use JWT in production)
 	response := models.LoginResponse{
 		Token: user.Email,
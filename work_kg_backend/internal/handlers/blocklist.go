@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/validation"
+)
+
+func HandleGetBlocklist(w http.ResponseWriter, r *http.Request) {
+	entries, err := database.GetBlocklist()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entries)
+}
+
+func HandleCreateBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	var entry models.BlocklistEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	entry.Value = strings.TrimSpace(entry.Value)
+	switch entry.Kind {
+	case "phone":
+		phone, err := validation.Phone(entry.Value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		entry.Value = phone
+	case "keyword":
+		if entry.Value == "" {
+			http.Error(w, "Value is required", http.StatusBadRequest)
+			return
+		}
+		entry.Value = strings.ToLower(entry.Value)
+	default:
+		http.Error(w, "Kind must be phone or keyword", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.AddBlocklistEntry(&entry); err != nil {
+		http.Error(w, "Failed to add blocklist entry", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "create", "blocklist", entry.ID, nil, entry)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func HandleDeleteBlocklistEntry(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	before, err := database.GetBlocklistEntry(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Blocklist entry not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := database.DeleteBlocklistEntry(id); err != nil {
+		http.Error(w, "Failed to delete blocklist entry", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "delete", "blocklist", id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+// HandleBulkJobs activates, deactivates, deletes, re-categorizes or extends
+// many jobs at once, selected by ID list and/or filter
+func HandleBulkJobs(w http.ResponseWriter, r *http.Request) {
+	var req models.JobBulkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	switch req.Action {
+	case models.BulkActivate, models.BulkDeactivate, models.BulkDelete, models.BulkExtend:
+	case models.BulkRecategorize:
+		subcategories, ok := models.Categories[req.Category]
+		if !ok {
+			http.Error(w, "Unknown category", http.StatusBadRequest)
+			return
+		}
+		if req.Subcategory != "" && !slices.Contains(subcategories, req.Subcategory) {
+			http.Error(w, "Unknown subcategory", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Action must be activate, deactivate, delete, recategorize or extend", http.StatusBadRequest)
+		return
+	}
+
+	// Refuse to touch every job by accident
+	if len(req.IDs) == 0 && (req.Filter == nil || req.Filter.IsEmpty()) {
+		http.Error(w, "Specify ids or a filter", http.StatusBadRequest)
+		return
+	}
+
+	matched, changed, err := database.BulkUpdateJobs(req)
+	if err != nil {
+		http.Error(w, "Failed to update jobs", http.StatusInternalServerError)
+		return
+	}
+
+	result := models.JobBulkResult{Action: req.Action, Matched: matched, Affected: len(changed), IDs: make([]int64, 0, len(changed))}
+	before := make(map[string]interface{}, len(changed))
+	for _, job := range changed {
+		result.IDs = append(result.IDs, job.ID)
+		before[strconv.FormatInt(job.ID, 10)] = bulkAuditBefore(req.Action, job)
+	}
+	if len(changed) > 0 {
+		// One entry per request keeps a large bulk action from flooding the log
+		recordAudit(r, "bulk_"+req.Action, "job", "", map[string]interface{}{"jobs": before}, map[string]interface{}{
+			"ids":     result.IDs,
+			"matched": matched,
+			"filter":  req.Filter,
+			"set":     bulkAuditAfter(req),
+		})
+	}
+
+	go syncBulkChannelPosts(req.Action, result.IDs)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// bulkAuditBefore returns the fields of the job a bulk action changes
+func bulkAuditBefore(action string, job models.Job) map[string]interface{} {
+	switch action {
+	case models.BulkActivate, models.BulkDeactivate:
+		return map[string]interface{}{"is_active": job.IsActive}
+	case models.BulkRecategorize:
+		return map[string]interface{}{"category": job.Category, "subcategory": job.Subcategory}
+	case models.BulkExtend:
+		return map[string]interface{}{"bumped_at": job.BumpedAt}
+	default:
+		return map[string]interface{}{"title": job.Title, "is_active": job.IsActive}
+	}
+}
+
+// bulkAuditAfter returns what a bulk action set those fields to
+func bulkAuditAfter(req models.JobBulkRequest) map[string]interface{} {
+	switch req.Action {
+	case models.BulkActivate, models.BulkDeactivate:
+		return map[string]interface{}{"is_active": req.Action == models.BulkActivate}
+	case models.BulkRecategorize:
+		return map[string]interface{}{"category": req.Category, "subcategory": req.Subcategory}
+	case models.BulkExtend:
+		return map[string]interface{}{"bumped_at": time.Now()}
+	default:
+		return map[string]interface{}{"deleted_at": time.Now()}
+	}
+}
+
+// syncBulkChannelPosts brings channel posts in line with the changed jobs
+func syncBulkChannelPosts(action string, ids []int64) {
+	for _, id := range ids {
+		switch action {
+		case models.BulkDelete:
+			bot.RemoveJobPosts(id)
+		case models.BulkActivate, models.BulkDeactivate, models.BulkRecategorize:
+			if job, err := database.GetJobByID(id); err == nil {
+				bot.PublishJob(*job)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/validation"
+)
+
+// Candidate endpoints live under /resumes/{id} so that recruiters can work
+// with anonymous resumes too; the data itself is kept by Telegram ID.
+
+func HandleGetCandidateNotes(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	notes, err := database.GetCandidateNotes(resume.TelegramID)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notes)
+}
+
+func HandleAddCandidateNote(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	var note models.CandidateNote
+	if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if note.Text, err = validation.Text(note.Text, 1, validation.MaxNoteLength); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	note.TelegramID = resume.TelegramID
+	note.AdminID = currentAdminID(r)
+
+	if err := database.AddCandidateNote(&note); err != nil {
+		http.Error(w, "Failed to add note", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "add_note", "resume", resume.ID, nil, note)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(note)
+}
+
+// HandleDeleteCandidateNote lets recruiters delete their own notes; admins can delete any
+func HandleDeleteCandidateNote(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+	noteID, _ := strconv.ParseInt(mux.Vars(r)["noteID"], 10, 64)
+
+	deleted, err := database.DeleteCandidateNote(resume.TelegramID, noteID, currentAdminID(r), r.Header.Get("X-User-Role") == "admin")
+	if err != nil {
+		http.Error(w, "Failed to delete note", http.StatusInternalServerError)
+		return
+	}
+	if !deleted {
+		http.Error(w, "Note not found", http.StatusNotFound)
+		return
+	}
+
+	recordAudit(r, "delete_note", "resume", resume.ID, map[string]interface{}{"note_id": noteID}, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func HandleSetCandidateStatus(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if _, ok := models.CandidateStatusLabels[req.Status]; !ok {
+		http.Error(w, "Unknown status", http.StatusBadRequest)
+		return
+	}
+
+	if err := database.SetCandidateStatus(resume.TelegramID, currentAdminID(r), req.Status); err != nil {
+		http.Error(w, "Failed to update status", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "set_status", "resume", resume.ID,
+		map[string]interface{}{"pipeline_status": resume.PipelineStatus},
+		map[string]interface{}{"pipeline_status": req.Status})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resume_id":       resume.ID,
+		"pipeline_status": req.Status,
+	})
+}
+
+func HandleAddCandidateTag(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	tag, err := validation.Text(strings.ToLower(req.Tag), 2, validation.MaxTagLength)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	added, err := database.AddCandidateTag(resume.TelegramID, currentAdminID(r), tag)
+	if err != nil {
+		http.Error(w, "Failed to add tag", http.StatusInternalServerError)
+		return
+	}
+	if added {
+		recordAudit(r, "add_tag", "resume", resume.ID, nil, map[string]interface{}{"tag": tag})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resume_id": resume.ID,
+		"tag":       tag,
+	})
+}
+
+func HandleRemoveCandidateTag(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+	tag := strings.ToLower(mux.Vars(r)["tag"])
+
+	if err := database.RemoveCandidateTag(resume.TelegramID, currentAdminID(r), tag); err != nil {
+		http.Error(w, "Failed to remove tag", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "remove_tag", "resume", resume.ID, map[string]interface{}{"tag": tag}, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func HandleGetCandidateTimeline(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := database.GetCandidateTimeline(resume.TelegramID)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/validation"
+)
+
+func HandleGetCompanies(w http.ResponseWriter, r *http.Request) {
+	companies, err := database.GetCompanies()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(companies)
+}
+
+// HandleGetCompany returns the company with all its vacancies and linked bot users
+func HandleGetCompany(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	company, err := database.GetCompanyByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Company not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	jobs, err := database.GetCompanyJobs(id, false)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	members, err := database.GetCompanyMembers(id)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+	for i := range members {
+		applyUserPrivacy(&members[i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"company": company,
+		"jobs":    jobs,
+		"members": members,
+	})
+}
+
+func HandleCreateCompany(w http.ResponseWriter, r *http.Request) {
+	var company models.Company
+	if err := json.NewDecoder(r.Body).Decode(&company); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateCompany(&company); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := database.CreateCompany(&company); err != nil {
+		http.Error(w, "Failed to create company", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "create", "company", company.ID, nil, company)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(company)
+}
+
+func HandleUpdateCompany(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	var company models.Company
+	if err := json.NewDecoder(r.Body).Decode(&company); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateCompany(&company); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, ok := loadCompany(w, id)
+	if !ok {
+		return
+	}
+
+	if err := database.UpdateCompany(id, &company); err != nil {
+		http.Error(w, "Failed to update company", http.StatusInternalServerError)
+		return
+	}
+
+	if after, err := database.GetCompanyByID(id); err == nil {
+		recordAudit(r, "update", "company", id, before, after)
+	}
+
+	company.ID = id
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(company)
+}
+
+func HandleDeleteCompany(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	before, ok := loadCompany(w, id)
+	if !ok {
+		return
+	}
+
+	if err := database.DeleteCompany(id); err != nil {
+		http.Error(w, "Failed to delete company", http.StatusInternalServerError)
+		return
+	}
+
+	recordAudit(r, "delete", "company", id, before, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleVerifyCompany sets or removes the company's verified badge
+func HandleVerifyCompany(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	var req struct {
+		Verified bool `json:"verified"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	before, ok := loadCompany(w, id)
+	if !ok {
+		return
+	}
+
+	if err := database.SetCompanyVerified(id, req.Verified); err != nil {
+		http.Error(w, "Failed to verify company", http.StatusInternalServerError)
+		return
+	}
+
+	company, ok := loadCompany(w, id)
+	if !ok {
+		return
+	}
+
+	recordAudit(r, "verify", "company", id, before, company)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(company)
+}
+
+// HandleAddCompanyMember links a bot user to the company so their
+// vacancies are published on its behalf
+func HandleAddCompanyMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	var req struct {
+		TelegramID int64 `json:"telegram_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.TelegramID == 0 {
+		http.Error(w, "telegram_id is required", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := loadCompany(w, id); !ok {
+		return
+	}
+
+	linked, err := database.LinkUserToCompany(req.TelegramID, &id)
+	if err != nil {
+		http.Error(w, "Failed to link user", http.StatusInternalServerError)
+		return
+	}
+	if !linked {
+		http.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	recordAudit(r, "add_member", "company", id, nil, map[string]interface{}{"telegram_id": req.TelegramID})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func HandleRemoveCompanyMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+	telegramID, _ := strconv.ParseInt(vars["telegramID"], 10, 64)
+
+	unlinked, err := database.UnlinkUserFromCompany(telegramID, id)
+	if err != nil {
+		http.Error(w, "Failed to unlink user", http.StatusInternalServerError)
+		return
+	}
+	if !unlinked {
+		http.Error(w, "Member not found", http.StatusNotFound)
+		return
+	}
+
+	recordAudit(r, "remove_member", "company", id, map[string]interface{}{"telegram_id": telegramID}, nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// loadCompany fetches the company, answering 404 or 500 when it can't
+func loadCompany(w http.ResponseWriter, id int64) (*models.Company, bool) {
+	company, err := database.GetCompanyByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Company not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return company, true
+}
+
+func validateCompany(company *models.Company) error {
+	var err error
+	if company.Name, err = validation.Text(company.Name, 2, validation.MaxCompanyLength); err != nil {
+		return err
+	}
+	if company.Phone != "" {
+		if company.Phone, err = validation.Phone(company.Phone); err != nil {
+			return err
+		}
+	}
+	return nil
+}
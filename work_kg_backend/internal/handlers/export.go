@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/spreadsheet"
+)
+
+const exportTimeLayout = "2006-01-02 15:04:05"
+
+var (
+	jobExportColumns = []string{"id", "title", "description", "category", "subcategory", "city", "salary", "phone",
+		"company", "company_id", "company_verified", "employer_rating", "employer_reviews", "is_active", "source",
+		"created_by", "risk_score", "risk_flags", "link", "created_at"}
+	userExportColumns = []string{"id", "telegram_id", "username", "first_name", "last_name", "phone", "phone_verified",
+		"contact_hidden", "company_id", "city", "specialty", "experience", "role", "pipeline_status", "tags", "created_at"}
+	resumeExportColumns = []string{"id", "telegram_id", "username", "name", "phone", "city", "specialty", "experience",
+		"work_history", "education", "skills", "desired_salary", "ready_to_relocate", "schedule", "visibility",
+		"anonymous", "contact_hidden", "pipeline_status", "tags", "created_at", "updated_at"}
+)
+
+// HandleExportJobs streams the job listing as CSV or XLSX (?format=)
+func HandleExportJobs(w http.ResponseWriter, r *http.Request) {
+	out, ok := startExport(w, r, "jobs", jobExportColumns)
+	if !ok {
+		return
+	}
+
+	rows := 0
+	err := database.StreamJobs(jobFilterFromRequest(r), func(job models.Job) error {
+		rows++
+		return out.WriteRow([]string{
+			formatInt(job.ID), job.Title, job.Description, job.Category, job.Subcategory, job.City, job.Salary, job.Phone,
+			job.Company, formatIntPtr(job.CompanyID), strconv.FormatBool(job.CompanyVerified),
+			strconv.FormatFloat(job.EmployerRating, 'f', 1, 64), strconv.Itoa(job.EmployerReviews),
+			strconv.FormatBool(job.IsActive), job.Source, formatInt(job.CreatedBy), strconv.Itoa(job.RiskScore),
+			strings.Join(job.RiskFlags, ", "), jobLink(job.ID), job.CreatedAt.Format(exportTimeLayout),
+		})
+	})
+	finishExport(r, out, "job", rows, err)
+}
+
+// HandleExportUsers streams all bot users as CSV or XLSX (?format=), with
+// the same privacy masking as the JSON listing
+func HandleExportUsers(w http.ResponseWriter, r *http.Request) {
+	out, ok := startExport(w, r, "users", userExportColumns)
+	if !ok {
+		return
+	}
+
+	rows := 0
+	err := database.StreamUsers(func(user models.User) error {
+		applyUserPrivacy(&user)
+		rows++
+
+		telegramID := ""
+		if user.TelegramID != 0 {
+			telegramID = formatInt(user.TelegramID)
+		}
+
+		return out.WriteRow([]string{
+			formatInt(user.ID), telegramID, user.Username, user.FirstName, user.LastName, user.Phone,
+			strconv.FormatBool(user.PhoneVerified), strconv.FormatBool(user.ContactHidden), formatIntPtr(user.CompanyID), user.City, user.Specialty,
+			user.Experience, user.Role, user.PipelineStatus, strings.Join(user.Tags, ", "),
+			user.CreatedAt.Format(exportTimeLayout),
+		})
+	})
+	finishExport(r, out, "user", rows, err)
+}
+
+// HandleExportResumes streams the resume listing as CSV or XLSX (?format=),
+// with the same privacy masking as the JSON listing
+func HandleExportResumes(w http.ResponseWriter, r *http.Request) {
+	out, ok := startExport(w, r, "resumes", resumeExportColumns)
+	if !ok {
+		return
+	}
+
+	rows := 0
+	err := database.StreamResumes(resumeFilterFromRequest(r), func(resume models.Resume) error {
+		applyResumePrivacy(&resume)
+		rows++
+
+		var workHistory []string
+		for _, work := range resume.WorkHistory {
+			workHistory = append(workHistory, fmt.Sprintf("%s — %s (%s)", work.Position, work.Employer, work.Period))
+		}
+
+		telegramID := ""
+		if resume.TelegramID != 0 {
+			telegramID = formatInt(resume.TelegramID)
+		}
+
+		return out.WriteRow([]string{
+			formatInt(resume.ID), telegramID, resume.Username, resume.Name, resume.Phone, resume.City,
+			resume.Specialty, resume.Experience, strings.Join(workHistory, "; "), resume.Education,
+			strings.Join(resume.Skills, ", "), resume.DesiredSalary, strconv.FormatBool(resume.ReadyToRelocate),
+			resume.Schedule, resume.Visibility, strconv.FormatBool(resume.Anonymous),
+			strconv.FormatBool(resume.ContactHidden), resume.PipelineStatus, strings.Join(resume.Tags, ", "),
+			resume.CreatedAt.Format(exportTimeLayout), resume.UpdatedAt.Format(exportTimeLayout),
+		})
+	})
+	finishExport(r, out, "resume", rows, err)
+}
+
+// startExport checks the requested format, sends the download headers and
+// writes the header row
+func startExport(w http.ResponseWriter, r *http.Request, name string, columns []string) (spreadsheet.Writer, bool) {
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = spreadsheet.CSV
+	}
+	contentType, ok := spreadsheet.ContentTypes[format]
+	if !ok {
+		http.Error(w, "Format must be csv or xlsx", http.StatusBadRequest)
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s.%s"`, name, time.Now().Format("2006-01-02"), format))
+
+	out, err := spreadsheet.NewWriter(w, format)
+	if err == nil {
+		err = out.WriteRow(columns)
+	}
+	if err != nil {
+		log.Printf("Error starting %s export: %v", name, err)
+		return nil, false
+	}
+	return out, true
+}
+
+// finishExport closes the file and records the export. Once rows are being
+// streamed the status code is already sent, so errors can only be logged.
+func finishExport(r *http.Request, out spreadsheet.Writer, entityType string, rows int, err error) {
+	if err != nil {
+		log.Printf("Error exporting %ss: %v", entityType, err)
+	}
+	if err := out.Close(); err != nil {
+		log.Printf("Error finishing %s export: %v", entityType, err)
+	}
+
+	recordAudit(r, "export", entityType, "", nil, map[string]interface{}{
+		"format": r.URL.Query().Get("format"),
+		"query":  r.URL.RawQuery,
+		"rows":   rows,
+	})
+}
+
+func formatInt(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func formatIntPtr(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return formatInt(*value)
+}
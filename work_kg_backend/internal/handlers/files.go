@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/storage"
+)
+
+// HandleGetFile serves a photo or CV sent to the bot by its Telegram file_id
+func HandleGetFile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	file, err := bot.OpenFile(vars["fileID"])
+	if err == storage.ErrNotFound {
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to load file", http.StatusBadGateway)
+		return
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	head, _ := reader.Peek(512)
+
+	w.Header().Set("Content-Type", http.DetectContentType(head))
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	io.Copy(w, reader)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/importer"
+	"work_kg_backend/internal/spreadsheet"
+)
+
+const maxImportSize = 10 << 20
+
+// HandleImportJobs imports vacancies from an uploaded CSV or XLSX file
+// (form field "file"). With ?dry_run=true it only returns the preview;
+// ?skip_invalid=true imports the valid rows even when others have errors.
+func HandleImportJobs(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Upload a csv or xlsx file in the file field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		if format, err = spreadsheet.FormatFromName(header.Filename); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	records, err := spreadsheet.ReadAll(file, format, importer.MaxRows+1)
+	if err != nil {
+		http.Error(w, "Invalid file: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	opts := importer.Options{}
+	opts.DryRun, _ = strconv.ParseBool(r.URL.Query().Get("dry_run"))
+	opts.SkipInvalid, _ = strconv.ParseBool(r.URL.Query().Get("skip_invalid"))
+
+	result, err := importer.Run(records, opts)
+	switch {
+	case errors.Is(err, importer.ErrEmpty), errors.Is(err, importer.ErrNoTitle), errors.Is(err, importer.ErrTooManyRows):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, importer.ErrInvalidRows):
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(result)
+		return
+	case err != nil:
+		http.Error(w, "Failed to import jobs", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if !result.DryRun {
+		status = http.StatusCreated
+		recordAudit(r, "import", "job", "", nil, map[string]interface{}{
+			"file":     header.Filename,
+			"imported": result.Imported,
+			"ids":      result.IDs,
+		})
+		go bot.PublishImported(result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
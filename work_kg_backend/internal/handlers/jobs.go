@@ -1,26 +1,54 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"work_kg_backend/internal/bot"
 	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/moderation"
+	"work_kg_backend/internal/validation"
 )
 
 func HandleGetJobs(w http.ResponseWriter, r *http.Request) {
-	jobs, err := database.GetAllJobs()
+	jobs, err := database.GetAllJobs(jobFilterFromRequest(r))
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
 		return
 	}
 
+	for i := range jobs {
+		jobs[i].Link = jobLink(jobs[i].ID)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(jobs)
 }
 
+func HandleGetJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	job, err := database.GetJobByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	job.Link = jobLink(job.ID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
 func HandleCreateJob(w http.ResponseWriter, r *http.Request) {
 	var job models.Job
 	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
@@ -28,14 +56,41 @@ func HandleCreateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validation.Job(&job); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := applyJobCompany(&job); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// created_by is the bot user behind a vacancy and drives rate limiting,
+	// so it is never taken from the request
+	job.CreatedBy = 0
 	job.Source = "admin"
 	job.IsActive = true
 
+	if err := moderation.Check(&job); errors.Is(err, moderation.ErrRateLimited) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to check job", http.StatusInternalServerError)
+		return
+	}
+
 	if err := database.CreateJob(&job); err != nil {
 		http.Error(w, "Failed to create job", http.StatusInternalServerError)
 		return
 	}
 
+	if job.IsActive {
+		go bot.PublishJob(job)
+	}
+
+	recordAudit(r, "create", "job", job.ID, nil, job)
+
+	job.Link = jobLink(job.ID)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(job)
@@ -51,12 +106,52 @@ func HandleUpdateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := validation.Job(&job); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	before, err := database.GetJobByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	// Coordinates sent back unchanged belong to the old city; dropping them
+	// moves the job to the new city's center
+	if job.City != before.City && sameCoordinate(job.Latitude, before.Latitude) && sameCoordinate(job.Longitude, before.Longitude) {
+		job.Latitude, job.Longitude = nil, nil
+	}
+
+	// A job stays with its company unless company_id is sent; 0 unlinks it
+	if job.CompanyID == nil {
+		job.CompanyID = before.CompanyID
+	} else if *job.CompanyID == 0 {
+		job.CompanyID = nil
+	}
+	if err := applyJobCompany(&job); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	if err := database.UpdateJob(id, &job); err != nil {
 		http.Error(w, "Failed to update job", http.StatusInternalServerError)
 		return
 	}
 
 	job.ID = id
+
+	// The stored job carries the employer rating the request body lacks
+	if after, err := database.GetJobByID(id); err == nil {
+		go bot.PublishJob(*after)
+		recordAudit(r, "update", "job", id, before, after)
+	}
+
+	job.Link = jobLink(id)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(job)
 }
@@ -65,10 +160,97 @@ func HandleDeleteJob(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, _ := strconv.ParseInt(vars["id"], 10, 64)
 
+	before, err := database.GetJobByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
 	if err := database.DeleteJob(id); err != nil {
 		http.Error(w, "Failed to delete job", http.StatusInternalServerError)
 		return
 	}
+	bot.RemoveJobPosts(id)
+
+	recordAudit(r, "delete", "job", id, before, nil)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// HandleGetDeletedJobs lists the trash, most recently deleted first
+func HandleGetDeletedJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := database.GetAllJobs(models.JobFilter{Deleted: true})
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func HandleRestoreJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	restored, err := database.RestoreJob(id)
+	if err != nil {
+		http.Error(w, "Failed to restore job", http.StatusInternalServerError)
+		return
+	}
+	if !restored {
+		http.Error(w, "Job not found in trash", http.StatusNotFound)
+		return
+	}
+
+	job, err := database.GetJobByID(id)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	if job.IsActive {
+		go bot.PublishJob(*job)
+	}
+
+	recordAudit(r, "restore", "job", id, nil, job)
+
+	job.Link = jobLink(id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// jobFilterFromRequest reads the job listing filters from the query string
+func jobFilterFromRequest(r *http.Request) models.JobFilter {
+	filter := models.JobFilter{}
+	filter.MinRisk, _ = strconv.Atoi(r.URL.Query().Get("min_risk"))
+	return filter
+}
+
+// jobLink is the bot deep link that opens the given job
+func jobLink(id int64) string {
+	return appConfig.JobLink(id)
+}
+
+// sameCoordinate reports whether sent is missing or equal to stored
+func sameCoordinate(sent, stored *float64) bool {
+	return sent == nil || (stored != nil && *sent == *stored)
+}
+
+// applyJobCompany copies the name and badge of the linked company onto the job
+func applyJobCompany(job *models.Job) error {
+	if job.CompanyID == nil {
+		return nil
+	}
+	company, err := database.GetCompanyByID(*job.CompanyID)
+	if err != nil {
+		return errors.New("company_id: компания не найдена")
+	}
+	job.Company = company.Name
+	job.CompanyVerified = company.Verified
+	return nil
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/matching"
+	"work_kg_backend/internal/models"
+)
+
+const defaultCandidateLimit = 20
+
+// HandleGetSuggestedCandidates lists the resumes that best match a job
+func HandleGetSuggestedCandidates(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	job, err := database.GetJobByID(id)
+	if err == sql.ErrNoRows {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultCandidateLimit
+	}
+
+	resumes, err := database.GetAllResumes(models.ResumeFilter{})
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	candidates := matching.SuggestCandidates(*job, resumes, limit)
+	for i := range candidates {
+		applyResumePrivacy(&candidates[i].Resume)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidates)
+}
@@ -32,3 +32,9 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		next(w, r)
 	}
 }
+
+// currentAdminID returns the ID of the admin authenticated by AuthMiddleware
+func currentAdminID(r *http.Request) int64 {
+	id, _ := strconv.ParseInt(r.Header.Get("X-User-ID"), 10, 64)
+	return id
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"work_kg_backend/internal/models"
+)
+
+// publicSchemas are the response types of the public API. Their OpenAPI
+// schemas are generated from the structs, so the document can't drift from
+// what the handlers actually return.
+var publicSchemas = map[string]reflect.Type{
+	"Job":      reflect.TypeOf(models.PublicJob{}),
+	"JobPage":  reflect.TypeOf(models.PublicJobPage{}),
+	"Category": reflect.TypeOf(models.PublicCategory{}),
+	"City":     reflect.TypeOf(models.PublicCity{}),
+}
+
+var (
+	openAPIOnce     sync.Once
+	openAPIDocument []byte
+)
+
+// HandleGetOpenAPI serves the OpenAPI 3 description of the public API
+func HandleGetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	openAPIOnce.Do(func() {
+		openAPIDocument, _ = json.MarshalIndent(buildOpenAPI(), "", "  ")
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", publicCacheControl)
+	w.Write(openAPIDocument)
+}
+
+type object = map[string]interface{}
+
+func buildOpenAPI() object {
+	schemas := object{
+		"Error": object{
+			"type":       "object",
+			"properties": object{"error": object{"type": "string"}},
+			"required":   []string{"error"},
+		},
+	}
+	for name, t := range publicSchemas {
+		schemas[name] = schemaFor(t)
+	}
+
+	return object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":       "Work.kg public API",
+			"version":     "1.0.0",
+			"description": "Read-only access to active vacancies published through the Work.kg bot and CRM.",
+		},
+		"servers": []object{{"url": "/public/v1"}},
+		"paths": object{
+			"/jobs": object{"get": object{
+				"summary":     "List active jobs",
+				"description": "Newest first. total counts every job matching the filters.",
+				"operationId": "listJobs",
+				"parameters": []object{
+					queryParam("category", "Category name, see /categories", object{"type": "string"}),
+					queryParam("subcategory", "Subcategory name", object{"type": "string"}),
+					queryParam("city", "City name, see /cities", object{"type": "string"}),
+					queryParam("company_id", "Only jobs of this company", object{"type": "integer", "format": "int64"}),
+					queryParam("q", "Words that must all appear in the title or description", object{"type": "string"}),
+					queryParam("since", "Only jobs created at or after this date or RFC 3339 time", object{"type": "string"}),
+					queryParam("limit", "Page size", object{"type": "integer", "minimum": 1, "maximum": maxPublicLimit, "default": defaultPublicLimit}),
+					queryParam("offset", "Number of jobs to skip", object{"type": "integer", "minimum": 0, "maximum": maxPublicOffset, "default": 0}),
+				},
+				"responses": object{
+					"200": jsonResponse("A page of jobs", ref("JobPage")),
+					"400": jsonResponse("Invalid parameter", ref("Error")),
+				},
+			}},
+			"/jobs/{id}": object{"get": object{
+				"summary":     "Get an active job",
+				"operationId": "getJob",
+				"parameters": []object{{
+					"name": "id", "in": "path", "required": true,
+					"schema": object{"type": "integer", "format": "int64"},
+				}},
+				"responses": object{
+					"200": jsonResponse("The job", ref("Job")),
+					"404": jsonResponse("No active job with this ID", ref("Error")),
+				},
+			}},
+			"/categories": object{"get": object{
+				"summary":     "List job categories and their subcategories",
+				"operationId": "listCategories",
+				"responses": object{
+					"200": jsonResponse("All categories", object{"type": "array", "items": ref("Category")}),
+				},
+			}},
+			"/cities": object{"get": object{
+				"summary":     "List cities with their center coordinates",
+				"operationId": "listCities",
+				"responses": object{
+					"200": jsonResponse("All cities", object{"type": "array", "items": ref("City")}),
+				},
+			}},
+		},
+		"components": object{"schemas": schemas},
+	}
+}
+
+func queryParam(name, description string, schema object) object {
+	return object{"name": name, "in": "query", "description": description, "schema": schema}
+}
+
+func jsonResponse(description string, schema object) object {
+	return object{
+		"description": description,
+		"content":     object{"application/json": object{"schema": schema}},
+	}
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaFor describes a Go type as an OpenAPI schema, following its JSON
+// encoding. Struct types listed in publicSchemas are referenced by name.
+func schemaFor(t reflect.Type) object {
+	switch {
+	case t == timeType:
+		return object{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Ptr:
+		schema := schemaFor(t.Elem())
+		schema["nullable"] = true
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := object{}
+		var required []string
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = fieldSchema(field.Type)
+			if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+				required = append(required, name)
+			}
+		}
+		schema := object{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.Slice, reflect.Array:
+		return object{"type": "array", "items": fieldSchema(t.Elem())}
+	case reflect.Bool:
+		return object{"type": "boolean"}
+	case reflect.Int, reflect.Int32:
+		return object{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return object{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return object{"type": "number"}
+	case reflect.Map:
+		return object{"type": "object", "additionalProperties": fieldSchema(t.Elem())}
+	}
+	return object{"type": "string"}
+}
+
+// fieldSchema references named public types and describes the rest inline
+func fieldSchema(t reflect.Type) object {
+	for name, schemaType := range publicSchemas {
+		if t == schemaType {
+			return ref(name)
+		}
+	}
+	return schemaFor(t)
+}
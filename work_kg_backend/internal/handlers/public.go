@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+const (
+	defaultPublicLimit = 20
+	maxPublicLimit     = 100
+	// maxPublicOffset stops clients from paging into expensive deep scans
+	maxPublicOffset = 10000
+	// publicCacheControl lets clients and proxies reuse public responses briefly
+	publicCacheControl = "public, max-age=60"
+)
+
+// HandleGetPublicJobs lists active jobs, newest first, with filters and
+// limit/offset pagination
+func HandleGetPublicJobs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.PublicJobFilter{
+		Category:    query.Get("category"),
+		Subcategory: query.Get("subcategory"),
+		City:        query.Get("city"),
+		Query:       query.Get("q"),
+		Limit:       defaultPublicLimit,
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPublicLimit {
+			writePublicError(w, "limit must be between 1 and 100", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 || offset > maxPublicOffset {
+			writePublicError(w, "offset must be between 0 and 10000", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+	if value := query.Get("company_id"); value != "" {
+		companyID, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			writePublicError(w, "company_id must be an integer", http.StatusBadRequest)
+			return
+		}
+		filter.CompanyID = companyID
+	}
+	since, err := parseTimeParam(query.Get("since"))
+	if err != nil {
+		writePublicError(w, "since must be a date or RFC 3339 time", http.StatusBadRequest)
+		return
+	}
+	filter.CreatedSince = since
+
+	jobs, total, err := database.GetPublicJobs(filter)
+	if err != nil {
+		writePublicError(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	page := models.PublicJobPage{Items: make([]models.PublicJob, 0, len(jobs)), Total: total, Limit: filter.Limit, Offset: filter.Offset}
+	for _, job := range jobs {
+		job.Link = jobLink(job.ID)
+		page.Items = append(page.Items, models.NewPublicJob(job))
+	}
+
+	writePublicJSON(w, page)
+}
+
+func HandleGetPublicJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		writePublicError(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	job, err := database.GetJobByID(id)
+	if err == sql.ErrNoRows || (err == nil && !job.IsActive) {
+		writePublicError(w, "Job not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		writePublicError(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	job.Link = jobLink(job.ID)
+	writePublicJSON(w, models.NewPublicJob(*job))
+}
+
+func HandleGetPublicCategories(w http.ResponseWriter, r *http.Request) {
+	categories := make([]models.PublicCategory, 0, len(models.Categories))
+	for name, subcategories := range models.Categories {
+		categories = append(categories, models.PublicCategory{
+			Name:          name,
+			Emoji:         models.CategoryEmojis[name],
+			Subcategories: subcategories,
+		})
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+
+	writePublicJSON(w, categories)
+}
+
+func HandleGetPublicCities(w http.ResponseWriter, r *http.Request) {
+	cities := make([]models.PublicCity, 0, len(models.Cities))
+	for _, name := range models.Cities {
+		point := models.CityLocations[name]
+		cities = append(cities, models.PublicCity{Name: name, Latitude: point.Latitude, Longitude: point.Longitude})
+	}
+
+	writePublicJSON(w, cities)
+}
+
+func writePublicJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", publicCacheControl)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writePublicError reports errors as JSON, as documented in the OpenAPI spec
+func writePublicError(w http.ResponseWriter, message string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
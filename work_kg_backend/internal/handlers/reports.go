@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/bot"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+type reviewReportsRequest struct {
+	// Action is "confirm" to keep the job hidden or "dismiss" to restore it
+	// when reports were what hid it
+	Action string `json:"action"`
+}
+
+func HandleGetReports(w http.ResponseWriter, r *http.Request) {
+	jobs, err := database.GetReportedJobs()
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobs)
+}
+
+func HandleReviewReports(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	var req reviewReportsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var status string
+	var active bool
+	switch req.Action {
+	case "confirm":
+		status, active = "confirmed", false
+	case "dismiss":
+		status = "dismissed"
+	default:
+		http.Error(w, "Action must be confirm or dismiss", http.StatusBadRequest)
+		return
+	}
+
+	job, err := database.GetJobByID(id)
+	if err != nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	wasActive := job.IsActive
+	if req.Action == "dismiss" {
+		// A job an admin deactivated stays hidden; only undo the automatic one
+		active = job.IsActive || job.DeactivatedBy == models.DeactivatedByReports
+	}
+	reviewed, err := database.ReviewJobReports(id, status)
+	if err != nil {
+		http.Error(w, "Failed to review reports", http.StatusInternalServerError)
+		return
+	}
+
+	// Saving also clears the automatic deactivation, so a confirmed job stays
+	// hidden if its reports are dismissed later
+	if job.IsActive != active || job.DeactivatedBy != "" {
+		if err := database.SetJobActive(id, active); err != nil {
+			http.Error(w, "Failed to update job", http.StatusInternalServerError)
+			return
+		}
+	}
+	if job.IsActive != active {
+		job.IsActive = active
+		go bot.PublishJob(*job)
+	}
+
+	recordAudit(r, "review_reports", "job", id,
+		map[string]interface{}{"is_active": wasActive},
+		map[string]interface{}{"is_active": job.IsActive, "reports_status": status, "reports_reviewed": reviewed})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"reviewed":  reviewed,
+		"is_active": job.IsActive,
+	})
+}
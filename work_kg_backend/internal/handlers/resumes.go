@@ -2,18 +2,138 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/bot"
 	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
 )
 
 func HandleGetResumes(w http.ResponseWriter, r *http.Request) {
-	resumes, err := database.GetAllResumes()
+	resumes, err := database.GetAllResumes(resumeFilterFromRequest(r))
 	if err != nil {
 		http.Error(w, "Database error", http.StatusInternalServerError)
 		return
 	}
 
+	for i := range resumes {
+		applyResumePrivacy(&resumes[i])
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resumes)
 }
+
+// HandleRequestResumeContact asks the job seeker, via the bot, to reveal
+// their contacts to the current admin
+func HandleRequestResumeContact(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	adminID := currentAdminID(r)
+	request, err := database.CreateContactRequest(resume.ID, adminID)
+	if err != nil {
+		http.Error(w, "Failed to create contact request", http.StatusInternalServerError)
+		return
+	}
+
+	requester := "Работодатель"
+	if admin, err := database.GetAdminByEmailWithoutPassword(r.Header.Get("X-User-Email")); err == nil && admin.Name != "" {
+		requester = admin.Name
+	}
+	go bot.SendContactRequest(request, resume, requester)
+
+	recordAudit(r, "request_contact", "resume", resume.ID, nil, request)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(request)
+}
+
+// HandleGetResumeContact reveals a resume's contacts and records who saw them
+func HandleGetResumeContact(w http.ResponseWriter, r *http.Request) {
+	resume, ok := loadVisibleResume(w, r)
+	if !ok {
+		return
+	}
+
+	adminID := currentAdminID(r)
+	if resumeContactHidden(resume) && !database.HasApprovedContactRequest(resume.ID, adminID) {
+		http.Error(w, "Candidate has not approved a contact request", http.StatusForbidden)
+		return
+	}
+
+	database.LogContactView(resume.ID, adminID, r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"resume_id":   resume.ID,
+		"telegram_id": resume.TelegramID,
+		"username":    resume.Username,
+		"name":        resume.Name,
+		"phone":       resume.Phone,
+	})
+}
+
+func HandleGetResumeContactViews(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	views, err := database.GetContactViews(id)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(views)
+}
+
+// loadVisibleResume fetches the resume from the URL, treating hidden ones as missing
+func loadVisibleResume(w http.ResponseWriter, r *http.Request) (*models.Resume, bool) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	resume, err := database.GetResumeByID(id)
+	if err != nil || resume.Visibility == models.VisibilityHidden {
+		http.Error(w, "Resume not found", http.StatusNotFound)
+		return nil, false
+	}
+	return resume, true
+}
+
+func resumeContactHidden(resume *models.Resume) bool {
+	return resume.Anonymous || resume.Visibility != models.VisibilityPublic
+}
+
+// resumeFilterFromRequest reads the resume listing filters from the query string
+func resumeFilterFromRequest(r *http.Request) models.ResumeFilter {
+	return models.ResumeFilter{
+		Tag:    strings.ToLower(r.URL.Query().Get("tag")),
+		Status: r.URL.Query().Get("status"),
+	}
+}
+
+// applyResumePrivacy strips what the job seeker chose not to show
+func applyResumePrivacy(resume *models.Resume) {
+	if !resumeContactHidden(resume) {
+		return
+	}
+
+	resume.ContactHidden = true
+	resume.Phone = ""
+	// The CV file usually lists the phone too
+	resume.CVFileID = ""
+	if resume.Anonymous {
+		resume.Name = fmt.Sprintf("Кандидат #%d", resume.ID)
+		resume.Username = ""
+		resume.TelegramID = 0
+		resume.PhotoFileID = ""
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+type moderateReviewRequest struct {
+	// Action is "approve" to publish the review or "reject" to hide it
+	Action string `json:"action"`
+}
+
+// HandleGetEmployerReviews lists employer reviews, filtered by ?status=
+func HandleGetEmployerReviews(w http.ResponseWriter, r *http.Request) {
+	reviews, err := database.GetEmployerReviews(r.URL.Query().Get("status"))
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reviews)
+}
+
+func HandleModerateEmployerReview(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, _ := strconv.ParseInt(vars["id"], 10, 64)
+
+	var req moderateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+
+	var status string
+	switch req.Action {
+	case "approve":
+		status = models.ReviewApproved
+	case "reject":
+		status = models.ReviewRejected
+	default:
+		http.Error(w, "Action must be approve or reject", http.StatusBadRequest)
+		return
+	}
+
+	found, err := database.ModerateEmployerReview(id, status)
+	if err != nil {
+		http.Error(w, "Failed to moderate review", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Review not found", http.StatusNotFound)
+		return
+	}
+
+	recordAudit(r, "moderate", "employer_review", id, nil, map[string]interface{}{"status": status})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":     id,
+		"status": status,
+	})
+}
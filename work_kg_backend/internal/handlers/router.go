@@ -3,11 +3,15 @@ package handlers
 import (
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+	"work_kg_backend/internal/config"
 )
 
+var appConfig *config.Config
+
 func SetupRouter() *mux.Router {
 	r := mux.NewRouter()
 
@@ -19,24 +23,87 @@ func SetupRouter() *mux.Router {
 	api.HandleFunc("/auth/me", AuthMiddleware(HandleGetMe)).Methods("GET")
 
 	// Jobs routes
-	api.HandleFunc("/jobs", HandleGetJobs).Methods("GET")
+	api.HandleFunc("/jobs", AuthMiddleware(HandleGetJobs)).Methods("GET")
 	api.HandleFunc("/jobs", AuthMiddleware(HandleCreateJob)).Methods("POST")
+	api.HandleFunc("/jobs/export", AuthMiddleware(HandleExportJobs)).Methods("GET")
+	api.HandleFunc("/jobs/import", AuthMiddleware(HandleImportJobs)).Methods("POST")
+	api.HandleFunc("/jobs/bulk", AuthMiddleware(HandleBulkJobs)).Methods("POST")
+	api.HandleFunc("/jobs/trash", AuthMiddleware(HandleGetDeletedJobs)).Methods("GET")
+	api.HandleFunc("/jobs/{id}", AuthMiddleware(HandleGetJob)).Methods("GET")
 	api.HandleFunc("/jobs/{id}", AuthMiddleware(HandleUpdateJob)).Methods("PUT")
 	api.HandleFunc("/jobs/{id}", AuthMiddleware(HandleDeleteJob)).Methods("DELETE")
+	api.HandleFunc("/jobs/{id}/candidates", AuthMiddleware(HandleGetSuggestedCandidates)).Methods("GET")
+	api.HandleFunc("/jobs/{id}/restore", AuthMiddleware(HandleRestoreJob)).Methods("POST")
+
+	// Companies routes
+	api.HandleFunc("/companies", AuthMiddleware(HandleGetCompanies)).Methods("GET")
+	api.HandleFunc("/companies", AuthMiddleware(HandleCreateCompany)).Methods("POST")
+	api.HandleFunc("/companies/{id}", AuthMiddleware(HandleGetCompany)).Methods("GET")
+	api.HandleFunc("/companies/{id}", AuthMiddleware(HandleUpdateCompany)).Methods("PUT")
+	api.HandleFunc("/companies/{id}", AuthMiddleware(HandleDeleteCompany)).Methods("DELETE")
+	api.HandleFunc("/companies/{id}/verify", AuthMiddleware(HandleVerifyCompany)).Methods("POST")
+	api.HandleFunc("/companies/{id}/members", AuthMiddleware(HandleAddCompanyMember)).Methods("POST")
+	api.HandleFunc("/companies/{id}/members/{telegramID}", AuthMiddleware(HandleRemoveCompanyMember)).Methods("DELETE")
+
+	// Reports routes
+	api.HandleFunc("/reports", AuthMiddleware(HandleGetReports)).Methods("GET")
+	api.HandleFunc("/reports/{id}/review", AuthMiddleware(HandleReviewReports)).Methods("POST")
+
+	// Employer reviews routes
+	api.HandleFunc("/reviews", AuthMiddleware(HandleGetEmployerReviews)).Methods("GET")
+	api.HandleFunc("/reviews/{id}/moderate", AuthMiddleware(HandleModerateEmployerReview)).Methods("POST")
+
+	// Blocklist routes
+	api.HandleFunc("/blocklist", AuthMiddleware(HandleGetBlocklist)).Methods("GET")
+	api.HandleFunc("/blocklist", AuthMiddleware(HandleCreateBlocklistEntry)).Methods("POST")
+	api.HandleFunc("/blocklist/{id}", AuthMiddleware(HandleDeleteBlocklistEntry)).Methods("DELETE")
 
 	// Users routes
 	api.HandleFunc("/users", AuthMiddleware(HandleGetUsers)).Methods("GET")
+	api.HandleFunc("/users/export", AuthMiddleware(HandleExportUsers)).Methods("GET")
 
 	// Resumes routes
 	api.HandleFunc("/resumes", AuthMiddleware(HandleGetResumes)).Methods("GET")
+	api.HandleFunc("/resumes/export", AuthMiddleware(HandleExportResumes)).Methods("GET")
+	api.HandleFunc("/resumes/{id}/contact", AuthMiddleware(HandleGetResumeContact)).Methods("GET")
+	api.HandleFunc("/resumes/{id}/contact-request", AuthMiddleware(HandleRequestResumeContact)).Methods("POST")
+	api.HandleFunc("/resumes/{id}/contact-views", AuthMiddleware(HandleGetResumeContactViews)).Methods("GET")
+	api.HandleFunc("/resumes/{id}/notes", AuthMiddleware(HandleGetCandidateNotes)).Methods("GET")
+	api.HandleFunc("/resumes/{id}/notes", AuthMiddleware(HandleAddCandidateNote)).Methods("POST")
+	api.HandleFunc("/resumes/{id}/notes/{noteID}", AuthMiddleware(HandleDeleteCandidateNote)).Methods("DELETE")
+	api.HandleFunc("/resumes/{id}/status", AuthMiddleware(HandleSetCandidateStatus)).Methods("PUT")
+	api.HandleFunc("/resumes/{id}/tags", AuthMiddleware(HandleAddCandidateTag)).Methods("POST")
+	api.HandleFunc("/resumes/{id}/tags/{tag}", AuthMiddleware(HandleRemoveCandidateTag)).Methods("DELETE")
+	api.HandleFunc("/resumes/{id}/timeline", AuthMiddleware(HandleGetCandidateTimeline)).Methods("GET")
+
+	// Files sent to the bot (resume photos, CVs, vacancy photos)
+	api.HandleFunc("/files/{fileID}", AuthMiddleware(HandleGetFile)).Methods("GET")
 
-	// Stats route
+	// Audit log
+	api.HandleFunc("/audit", AuthMiddleware(HandleGetAuditLog)).Methods("GET")
+
+	// Bot analytics
+	api.HandleFunc("/analytics/funnel", AuthMiddleware(HandleGetFunnel)).Methods("GET")
+	api.HandleFunc("/analytics/searches", AuthMiddleware(HandleGetSearchStats)).Methods("GET")
+	api.HandleFunc("/analytics/active-users", AuthMiddleware(HandleGetActiveUsers)).Methods("GET")
+
+	// Stats routes
 	api.HandleFunc("/stats", AuthMiddleware(HandleGetStats)).Methods("GET")
+	api.HandleFunc("/stats/timeseries", AuthMiddleware(HandleGetTimeSeries)).Methods("GET")
+
+	// Public read-only API, open to any site
+	public := r.PathPrefix("/public/v1").Subrouter()
+	public.HandleFunc("/jobs", HandleGetPublicJobs).Methods("GET")
+	public.HandleFunc("/jobs/{id}", HandleGetPublicJob).Methods("GET")
+	public.HandleFunc("/categories", HandleGetPublicCategories).Methods("GET")
+	public.HandleFunc("/cities", HandleGetPublicCities).Methods("GET")
+	public.HandleFunc("/openapi.json", HandleGetOpenAPI).Methods("GET")
 
 	return r
 }
 
-func StartServer(port string) {
+func StartServer(cfg *config.Config) {
+	appConfig = cfg
 	r := SetupRouter()
 
 	// CORS
@@ -47,8 +114,21 @@ func StartServer(port string) {
 		AllowCredentials: true,
 	})
 
-	handler := c.Handler(r)
+	publicCORS := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "OPTIONS"},
+	})
+	crmHandler := c.Handler(r)
+	publicHandler := publicCORS.Handler(r)
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if strings.HasPrefix(req.URL.Path, "/public/") {
+			publicHandler.ServeHTTP(w, req)
+			return
+		}
+		crmHandler.ServeHTTP(w, req)
+	})
 
-	log.Printf("HTTP server starting on :%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	log.Printf("HTTP server starting on :%s", cfg.ServerPort)
+	log.Fatal(http.ListenAndServe(":"+cfg.ServerPort, handler))
 }
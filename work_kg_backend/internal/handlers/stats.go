@@ -3,13 +3,161 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
 )
 
+// maxTimeSeriesBuckets keeps daily series over long ranges from getting huge
+const maxTimeSeriesBuckets = 1000
+
+// timeSeriesMetrics are always reported, even without data in the range
+var timeSeriesMetrics = []string{models.MetricJobs, models.MetricUsers, models.MetricResumes, models.MetricApplications}
+
 func HandleGetStats(w http.ResponseWriter, r *http.Request) {
 	stats := database.GetStats()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(stats)
 }
+
+// HandleGetTimeSeries counts new jobs, users, resumes and applications per
+// day, week or month (?interval=) between ?from= and ?to= (dates, both
+// inclusive), optionally broken down by ?group_by=category|city|source.
+// Without from the series covers the last 30 buckets up to today.
+func HandleGetTimeSeries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.TimeSeriesFilter{Interval: query.Get("interval"), GroupBy: query.Get("group_by")}
+
+	if filter.Interval == "" {
+		filter.Interval = models.IntervalDay
+	}
+	if filter.Interval != models.IntervalDay && filter.Interval != models.IntervalWeek && filter.Interval != models.IntervalMonth {
+		http.Error(w, "Interval must be day, week or month", http.StatusBadRequest)
+		return
+	}
+	switch filter.GroupBy {
+	case "", models.GroupByCategory, models.GroupByCity, models.GroupBySource:
+	default:
+		http.Error(w, "group_by must be category, city or source", http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		http.Error(w, "Invalid from", http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		http.Error(w, "Invalid to", http.StatusBadRequest)
+		return
+	}
+
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	// The end date is inclusive, so the range runs to the following midnight
+	filter.To = truncateToBucket(to, models.IntervalDay).AddDate(0, 0, 1)
+	if from.IsZero() {
+		from = nextBucket(filter.To, filter.Interval, -30)
+	}
+	filter.From = truncateToBucket(from, filter.Interval)
+	if !filter.From.Before(filter.To) {
+		http.Error(w, "from must be before to", http.StatusBadRequest)
+		return
+	}
+
+	var buckets []time.Time
+	for bucket := filter.From; bucket.Before(filter.To); bucket = nextBucket(bucket, filter.Interval, 1) {
+		if len(buckets) == maxTimeSeriesBuckets {
+			http.Error(w, "Date range is too long for this interval", http.StatusBadRequest)
+			return
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	counts, err := database.GetTimeSeries(filter)
+	if err != nil {
+		http.Error(w, "Database error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(buildTimeSeries(filter, buckets, counts))
+}
+
+// buildTimeSeries lays the counts out as zero-filled lines over the buckets
+func buildTimeSeries(filter models.TimeSeriesFilter, buckets []time.Time, counts []models.TimeSeriesCount) models.TimeSeries {
+	series := models.TimeSeries{
+		From:     filter.From,
+		To:       filter.To,
+		Interval: filter.Interval,
+		GroupBy:  filter.GroupBy,
+		Buckets:  buckets,
+		Series:   make([]models.TimeSeriesLine, 0),
+		Totals:   make(map[string]int),
+	}
+
+	bucketIndex := make(map[string]int, len(buckets))
+	for i, bucket := range buckets {
+		bucketIndex[bucket.Format("2006-01-02")] = i
+	}
+
+	lineIndex := make(map[[2]string]int)
+	line := func(metric, group string) *models.TimeSeriesLine {
+		key := [2]string{metric, group}
+		i, ok := lineIndex[key]
+		if !ok {
+			i = len(series.Series)
+			lineIndex[key] = i
+			series.Series = append(series.Series, models.TimeSeriesLine{Metric: metric, Group: group, Counts: make([]int, len(buckets))})
+		}
+		return &series.Series[i]
+	}
+
+	for _, metric := range timeSeriesMetrics {
+		series.Totals[metric] = 0
+		if filter.GroupBy == "" {
+			line(metric, "")
+		}
+	}
+
+	for _, count := range counts {
+		i, ok := bucketIndex[count.Bucket.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		l := line(count.Metric, count.Group)
+		l.Counts[i] += count.Count
+		l.Total += count.Count
+		series.Totals[count.Metric] += count.Count
+	}
+
+	return series
+}
+
+// truncateToBucket returns the start of the bucket containing t, matching
+// PostgreSQL's date_trunc: weeks start on Monday
+func truncateToBucket(t time.Time, interval string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case models.IntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case models.IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// nextBucket moves n buckets forward, or back when n is negative
+func nextBucket(t time.Time, interval string, n int) time.Time {
+	switch interval {
+	case models.IntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case models.IntervalMonth:
+		return t.AddDate(0, n, 0)
+	}
+	return t.AddDate(0, 0, n)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
 )
 
 func HandleGetUsers(w http.ResponseWriter, r *http.Request) {
@@ -13,7 +14,29 @@ func HandleGetUsers(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Database error", http.StatusInternalServerError)
 		return
 	}
+	for i := range users {
+		applyUserPrivacy(&users[i])
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(users)
 }
+
+// applyUserPrivacy hides the resume form data copied into the user record
+// when the resume itself keeps it hidden, matching applyResumePrivacy
+func applyUserPrivacy(user *models.User) {
+	if !user.ResumeAnonymous && user.ResumeVisibility == models.VisibilityPublic {
+		return
+	}
+
+	user.ContactHidden = true
+	user.Phone = ""
+	if user.ResumeAnonymous || user.ResumeVisibility == models.VisibilityHidden {
+		user.FirstName = ""
+		user.LastName = ""
+	}
+	if user.ResumeAnonymous {
+		user.Username = ""
+		user.TelegramID = 0
+	}
+}
@@ -0,0 +1,261 @@
+// Package importer turns partner spreadsheets into vacancies. The same
+// rules apply whether the file comes through the CRM API or the command line.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+	"work_kg_backend/internal/moderation"
+	"work_kg_backend/internal/validation"
+)
+
+// Source is stored on every imported job
+const Source = "import"
+
+// MaxRows caps how many vacancies one file may contain
+const MaxRows = 5000
+
+var (
+	ErrEmpty       = errors.New("file has no rows")
+	ErrNoTitle     = errors.New("file has no title column")
+	ErrTooManyRows = fmt.Errorf("file has more than %d rows", MaxRows)
+	// ErrInvalidRows stops an import when some rows failed validation and
+	// the caller didn't ask to skip them
+	ErrInvalidRows = errors.New("some rows are invalid")
+)
+
+// columnAliases maps header names, lowercased, to job fields
+var columnAliases = map[string]string{
+	"title":         "title",
+	"название":      "title",
+	"вакансия":      "title",
+	"должность":     "title",
+	"description":   "description",
+	"описание":      "description",
+	"category":      "category",
+	"категория":     "category",
+	"subcategory":   "subcategory",
+	"подкатегория":  "subcategory",
+	"специальность": "subcategory",
+	"city":          "city",
+	"город":         "city",
+	"salary":        "salary",
+	"зарплата":      "salary",
+	"phone":         "phone",
+	"телефон":       "phone",
+	"company":       "company",
+	"компания":      "company",
+	"company_id":    "company_id",
+}
+
+// Options control what Run does with the parsed rows
+type Options struct {
+	// DryRun only validates and previews the rows
+	DryRun bool
+	// SkipInvalid imports the valid rows even when others failed
+	SkipInvalid bool
+}
+
+// Run validates the records, the first of which is the header row, and
+// unless it's a dry run saves the valid jobs in one transaction. The result
+// lists every row with its errors; with invalid rows and no SkipInvalid
+// nothing is saved and ErrInvalidRows is returned alongside it.
+func Run(records [][]string, opts Options) (*models.ImportResult, error) {
+	result, err := Parse(records)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = opts.DryRun
+	if opts.DryRun {
+		return result, nil
+	}
+	if result.Invalid > 0 && !opts.SkipInvalid {
+		return result, ErrInvalidRows
+	}
+
+	var jobs []*models.Job
+	for _, row := range result.Rows {
+		if row.Job != nil {
+			jobs = append(jobs, row.Job)
+		}
+	}
+	if len(jobs) == 0 {
+		return result, nil
+	}
+
+	if err := database.ImportJobs(jobs); err != nil {
+		return result, err
+	}
+	result.Imported = len(jobs)
+	for _, job := range jobs {
+		result.IDs = append(result.IDs, job.ID)
+	}
+	return result, nil
+}
+
+// Parse maps the records to jobs and validates each one
+func Parse(records [][]string) (*models.ImportResult, error) {
+	if len(records) == 0 {
+		return nil, ErrEmpty
+	}
+	if len(records)-1 > MaxRows {
+		return nil, ErrTooManyRows
+	}
+
+	result := &models.ImportResult{Rows: make([]models.ImportRow, 0, len(records)-1)}
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		header = strings.ToLower(strings.TrimSpace(header))
+		field, ok := columnAliases[header]
+		if !ok {
+			if header != "" {
+				result.IgnoredColumns = append(result.IgnoredColumns, header)
+			}
+			continue
+		}
+		if _, seen := columns[field]; !seen {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, ErrNoTitle
+	}
+
+	checker := moderation.NewChecker()
+	// seen maps each valid row's content to its line, to catch repeated rows
+	seen := make(map[string]int)
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+
+		get := func(field string) string {
+			if index, ok := columns[field]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		row := models.ImportRow{Line: i + 2}
+		job := &models.Job{
+			Title:       get("title"),
+			Description: get("description"),
+			Category:    get("category"),
+			Subcategory: get("subcategory"),
+			City:        get("city"),
+			Salary:      get("salary"),
+			Phone:       get("phone"),
+			Company:     get("company"),
+			Source:      Source,
+			IsActive:    true,
+		}
+		row.Errors = validateJob(job, get("company_id"), checker)
+		if len(row.Errors) == 0 {
+			key := strings.ToLower(strings.Join([]string{job.Title, job.Description, job.Category, job.Subcategory,
+				job.City, job.Salary, job.Phone, job.Company}, "\x00"))
+			if line, ok := seen[key]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("повторяет строку %d", line))
+			} else {
+				seen[key] = row.Line
+			}
+		}
+
+		result.Total++
+		if len(row.Errors) > 0 {
+			result.Invalid++
+		} else {
+			result.Valid++
+			row.Job = job
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	return result, nil
+}
+
+// validateJob normalizes the job and returns everything wrong with it
+func validateJob(job *models.Job, companyID string, checker *moderation.Checker) []string {
+	var errs []string
+
+	if err := validation.Job(job); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	category, ok := lookup(categoryNames(), job.Category)
+	switch {
+	case job.Category == "":
+		errs = append(errs, "category: обязательное поле")
+	case !ok:
+		errs = append(errs, fmt.Sprintf("category: неизвестная категория «%s»", job.Category))
+	default:
+		job.Category = category
+		if job.Subcategory != "" {
+			subcategory, ok := lookup(models.Categories[category], job.Subcategory)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("subcategory: «%s» нет в категории «%s»", job.Subcategory, category))
+			}
+			job.Subcategory = subcategory
+		}
+	}
+
+	city, ok := lookup(models.Cities, job.City)
+	switch {
+	case job.City == "":
+		errs = append(errs, "city: обязательное поле")
+	case !ok:
+		errs = append(errs, fmt.Sprintf("city: неизвестный город «%s»", job.City))
+	default:
+		job.City = city
+	}
+
+	if companyID != "" {
+		id, err := strconv.ParseInt(companyID, 10, 64)
+		if err != nil {
+			errs = append(errs, "company_id: должен быть числом")
+		} else if company, err := database.GetCompanyByID(id); err != nil {
+			errs = append(errs, "company_id: компания не найдена")
+		} else {
+			job.CompanyID = &company.ID
+			job.Company = company.Name
+			job.CompanyVerified = company.Verified
+		}
+	}
+
+	if len(errs) == 0 {
+		// Scores risk and holds suspicious jobs back for moderation
+		checker.Check(job)
+	}
+	return errs
+}
+
+// lookup finds value in the list ignoring case and returns its canonical spelling
+func lookup(list []string, value string) (string, bool) {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return item, true
+		}
+	}
+	return value, false
+}
+
+func categoryNames() []string {
+	names := make([]string, 0, len(models.Categories))
+	for name := range models.Categories {
+		names = append(names, name)
+	}
+	return names
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
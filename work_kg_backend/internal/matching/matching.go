@@ -0,0 +1,189 @@
+package matching
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"work_kg_backend/internal/models"
+)
+
+// MinScore is the lowest score still worth recommending
+const MinScore = 40
+
+// Score weights. Score awards at most one weight from each group (specialty
+// or skills, city or relocation, salary, recency), so the best match gets
+// specialty + city + salary + recent = 100.
+const (
+	specialtyWeight = 50
+	skillsWeight    = 30
+	cityWeight      = 25
+	relocateWeight  = 15
+	salaryWeight    = 15
+	recentWeight    = 10
+)
+
+// stemLength is how many leading letters two words must share to match,
+// so that Russian word forms like "повар" and "повара" are equal
+const stemLength = 5
+
+var salaryNumber = regexp.MustCompile(`\d[\d\s]*`)
+
+// Score rates how well a job suits a resume from 0 to 100 and lists why
+func Score(job models.Job, resume models.Resume) (int, []string) {
+	score := 0
+	var reasons []string
+
+	switch {
+	case resume.Specialty != "" && strings.EqualFold(strings.TrimSpace(resume.Specialty), job.Subcategory):
+		score += specialtyWeight
+		reasons = append(reasons, models.MatchSpecialty)
+	case overlaps(stems(resume.Specialty), stems(job.Subcategory+" "+job.Title)):
+		score += skillsWeight
+		reasons = append(reasons, models.MatchSpecialty)
+	case overlaps(stems(strings.Join(resume.Skills, " ")), stems(job.Title+" "+job.Description)):
+		score += skillsWeight
+		reasons = append(reasons, models.MatchSkills)
+	}
+
+	switch {
+	case resume.City != "" && resume.City == job.City:
+		score += cityWeight
+		reasons = append(reasons, models.MatchCity)
+	case resume.ReadyToRelocate:
+		score += relocateWeight
+		reasons = append(reasons, models.MatchRelocate)
+	}
+
+	desired, desiredOK := salaryBounds(resume.DesiredSalary)
+	offered, offeredOK := salaryBounds(job.Salary)
+	switch {
+	case desiredOK && offeredOK:
+		if desired[0] <= offered[1] {
+			score += salaryWeight
+			reasons = append(reasons, models.MatchSalary)
+		}
+	default:
+		// Unknown on either side: neither a match nor a mismatch
+		score += salaryWeight / 2
+	}
+
+	age := time.Since(job.CreatedAt)
+	switch {
+	case age < 3*24*time.Hour:
+		score += recentWeight
+		reasons = append(reasons, models.MatchRecent)
+	case age < 7*24*time.Hour:
+		score += recentWeight / 2
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	return score, reasons
+}
+
+// RecommendJobs returns the best jobs for the resume, best first
+func RecommendJobs(resume models.Resume, jobs []models.Job, limit int) []models.JobMatch {
+	matches := make([]models.JobMatch, 0)
+	for _, job := range jobs {
+		if job.CreatedBy == resume.TelegramID {
+			continue
+		}
+		score, reasons := Score(job, resume)
+		if score < MinScore {
+			continue
+		}
+		matches = append(matches, models.JobMatch{Job: job, Score: score, Reasons: reasons})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Job.CreatedAt.After(matches[j].Job.CreatedAt)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// SuggestCandidates returns the best resumes for the job, best first
+func SuggestCandidates(job models.Job, resumes []models.Resume, limit int) []models.CandidateMatch {
+	matches := make([]models.CandidateMatch, 0)
+	for _, resume := range resumes {
+		if resume.TelegramID == job.CreatedBy {
+			continue
+		}
+		score, reasons := Score(job, resume)
+		if score < MinScore {
+			continue
+		}
+		matches = append(matches, models.CandidateMatch{Resume: resume, Score: score, Reasons: reasons})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Resume.UpdatedAt.After(matches[j].Resume.UpdatedAt)
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// stems reduces the text to the set of its word stems
+func stems(text string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) < 3 {
+			continue
+		}
+		if len(runes) > stemLength {
+			runes = runes[:stemLength]
+		}
+		set[string(runes)] = true
+	}
+	return set
+}
+
+func overlaps(a, b map[string]bool) bool {
+	for stem := range a {
+		if b[stem] {
+			return true
+		}
+	}
+	return false
+}
+
+// salaryBounds reads the lowest and highest amount mentioned in a salary text.
+// A single amount is both bounds.
+func salaryBounds(text string) ([2]int, bool) {
+	var bounds [2]int
+	numbers := salaryNumber.FindAllString(text, -1)
+	found := false
+	for _, number := range numbers {
+		value, err := strconv.Atoi(strings.ReplaceAll(strings.TrimSpace(number), " ", ""))
+		if err != nil || value == 0 {
+			continue
+		}
+		if !found || value < bounds[0] {
+			bounds[0] = value
+		}
+		if !found || value > bounds[1] {
+			bounds[1] = value
+		}
+		found = true
+	}
+	return bounds, found
+}
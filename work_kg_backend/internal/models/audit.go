@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry records one change made through the CRM API
+type AuditEntry struct {
+	ID         int64                  `json:"id"`
+	AdminID    *int64                 `json:"admin_id"`
+	AdminEmail string                 `json:"admin_email"`
+	Action     string                 `json:"action"`
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Before     json.RawMessage        `json:"before"`
+	After      json.RawMessage        `json:"after"`
+	Changes    map[string]AuditChange `json:"changes"`
+	Method     string                 `json:"method"`
+	Path       string                 `json:"path"`
+	IPAddress  string                 `json:"ip_address"`
+	UserAgent  string                 `json:"user_agent"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// AuditChange is the old and new value of one changed field
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditFilter narrows the audit log listing
+type AuditFilter struct {
+	AdminID    int64
+	Action     string
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+	Offset     int
+}
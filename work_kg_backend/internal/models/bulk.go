@@ -0,0 +1,47 @@
+package models
+
+// Bulk job actions available in the CRM API
+const (
+	BulkActivate     = "activate"
+	BulkDeactivate   = "deactivate"
+	BulkDelete       = "delete"
+	BulkRecategorize = "recategorize"
+	BulkExtend       = "extend"
+)
+
+// JobBulkRequest applies one action to the listed jobs or to every job
+// matching the filter
+type JobBulkRequest struct {
+	Action      string         `json:"action"`
+	IDs         []int64        `json:"ids"`
+	Filter      *JobBulkFilter `json:"filter"`
+	Category    string         `json:"category"`
+	Subcategory string         `json:"subcategory"`
+}
+
+// JobBulkFilter selects jobs for a bulk action. Empty fields match any job.
+type JobBulkFilter struct {
+	Source        string `json:"source"`
+	Category      string `json:"category"`
+	Subcategory   string `json:"subcategory"`
+	City          string `json:"city"`
+	CompanyID     *int64 `json:"company_id"`
+	IsActive      *bool  `json:"is_active"`
+	OlderThanDays int    `json:"older_than_days"`
+	MinRisk       int    `json:"min_risk"`
+}
+
+// IsEmpty reports whether the filter would match every job
+func (f JobBulkFilter) IsEmpty() bool {
+	return f.Source == "" && f.Category == "" && f.Subcategory == "" && f.City == "" &&
+		f.CompanyID == nil && f.IsActive == nil && f.OlderThanDays <= 0 && f.MinRisk <= 0
+}
+
+// JobBulkResult summarizes a bulk action: how many jobs matched and which
+// of them were actually changed
+type JobBulkResult struct {
+	Action   string  `json:"action"`
+	Matched  int     `json:"matched"`
+	Affected int     `json:"affected"`
+	IDs      []int64 `json:"ids"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Candidate pipeline statuses, in pipeline order
+const (
+	CandidateNew       = "new"
+	CandidateContacted = "contacted"
+	CandidateInterview = "interview"
+	CandidateOffer     = "offer"
+	CandidateHired     = "hired"
+	CandidateRejected  = "rejected"
+)
+
+// CandidateStatusLabels maps pipeline statuses to the labels shown in the CRM
+var CandidateStatusLabels = map[string]string{
+	CandidateNew:       "Новый",
+	CandidateContacted: "Связались",
+	CandidateInterview: "Собеседование",
+	CandidateOffer:     "Предложение",
+	CandidateHired:     "Принят",
+	CandidateRejected:  "Отказ",
+}
+
+// ResumeFilter narrows resume listings in the CRM API
+type ResumeFilter struct {
+	Tag    string
+	Status string
+}
+
+type CandidateNote struct {
+	ID         int64     `json:"id"`
+	TelegramID int64     `json:"-"`
+	AdminID    int64     `json:"admin_id"`
+	AdminName  string    `json:"admin_name"`
+	Text       string    `json:"text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TimelineEvent is one interaction with a candidate: their own actions in
+// the bot and everything recruiters did in the CRM
+type TimelineEvent struct {
+	Kind      string    `json:"kind"`
+	Details   string    `json:"details"`
+	AdminID   *int64    `json:"admin_id"`
+	AdminName string    `json:"admin_name,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
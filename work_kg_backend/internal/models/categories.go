@@ -19,3 +19,5 @@ var CategoryEmojis = map[string]string{
 	"Продажи":       "🛒",
 	"Транспорт":     "🚗",
 }
+
+var Schedules = []string{"Полный день", "Сменный график", "Гибкий график", "Удалённая работа", "Вахта"}
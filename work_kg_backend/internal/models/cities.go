@@ -0,0 +1,46 @@
+package models
+
+import "math"
+
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// CityLocations holds the city center coordinates for every city in Cities
+var CityLocations = map[string]GeoPoint{
+	"Бишкек":     {Latitude: 42.8746, Longitude: 74.5698},
+	"Ош":         {Latitude: 40.5283, Longitude: 72.7985},
+	"Талас":      {Latitude: 42.5228, Longitude: 72.2427},
+	"Нарын":      {Latitude: 41.4287, Longitude: 75.9911},
+	"Каракол":    {Latitude: 42.4907, Longitude: 78.3936},
+	"Жалал-Абад": {Latitude: 40.9333, Longitude: 73.0000},
+	"Чолпон-Ата": {Latitude: 42.6490, Longitude: 77.0823},
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two points
+func DistanceKm(a, b GeoPoint) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// NearestCity returns the known city closest to the point and the distance to it
+func NearestCity(point GeoPoint) (string, float64) {
+	nearest := ""
+	best := math.MaxFloat64
+	for _, city := range Cities {
+		distance := DistanceKm(point, CityLocations[city])
+		if distance < best {
+			nearest = city
+			best = distance
+		}
+	}
+	return nearest, best
+}
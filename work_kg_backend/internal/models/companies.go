@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Company struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	LogoURL     string     `json:"logo_url"`
+	Phone       string     `json:"phone"`
+	Email       string     `json:"email"`
+	Website     string     `json:"website"`
+	Verified    bool       `json:"verified"`
+	VerifiedAt  *time.Time `json:"verified_at"`
+	ActiveJobs  int        `json:"active_jobs"`
+	Rating      float64    `json:"rating"`
+	ReviewCount int        `json:"review_count"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Bot events recorded for analytics
+const (
+	EventCommand        = "command"
+	EventCallback       = "callback"
+	EventSearch         = "search"
+	EventWizardStep     = "wizard_step"
+	EventWizardError    = "wizard_error"
+	EventWizardComplete = "wizard_complete"
+	EventWizardCancel   = "wizard_cancel"
+)
+
+// BotEvent is one thing a user did in the bot. Name is the command,
+// callback action, search kind or wizard step; Properties hold the details.
+type BotEvent struct {
+	ID         int64                  `json:"id"`
+	TelegramID int64                  `json:"telegram_id"`
+	Event      string                 `json:"event"`
+	Name       string                 `json:"name"`
+	Properties map[string]interface{} `json:"properties"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// Funnel shows how far users get through a bot wizard. Conversion is the
+// share of users who started and completed it, in percent.
+type Funnel struct {
+	Wizard     string       `json:"wizard"`
+	Started    int          `json:"started"`
+	Completed  int          `json:"completed"`
+	Cancelled  int          `json:"cancelled"`
+	Conversion float64      `json:"conversion"`
+	Steps      []FunnelStep `json:"steps"`
+}
+
+// FunnelStep counts the users who reached a wizard step and the invalid
+// answers given there. DropOff is the percentage of users from the previous
+// step who never reached this one.
+type FunnelStep struct {
+	Step    string  `json:"step"`
+	Users   int     `json:"users"`
+	Errors  int     `json:"errors"`
+	DropOff float64 `json:"drop_off"`
+}
+
+// SearchStat groups identical searches
+type SearchStat struct {
+	Kind        string    `json:"kind"`
+	Category    string    `json:"category"`
+	Subcategory string    `json:"subcategory"`
+	City        string    `json:"city"`
+	Query       string    `json:"query"`
+	Searches    int       `json:"searches"`
+	Users       int       `json:"users"`
+	ZeroResults int       `json:"zero_results"`
+	LastAt      time.Time `json:"last_at"`
+}
+
+// SearchStatFilter narrows the search statistics
+type SearchStatFilter struct {
+	From        time.Time
+	To          time.Time
+	ZeroResults bool
+	Limit       int
+}
+
+// ActiveUsers counts distinct users doing anything in the bot
+type ActiveUsers struct {
+	DAU     int           `json:"dau"`
+	MAU     int           `json:"mau"`
+	Daily   []ActiveCount `json:"daily"`
+	Monthly []ActiveCount `json:"monthly"`
+}
+
+type ActiveCount struct {
+	Date  time.Time `json:"date"`
+	Users int       `json:"users"`
+}
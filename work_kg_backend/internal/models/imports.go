@@ -0,0 +1,21 @@
+package models
+
+// ImportRow is one spreadsheet row of a vacancy import. Job is set when the
+// row is valid, Errors otherwise.
+type ImportRow struct {
+	Line   int      `json:"line"`
+	Job    *Job     `json:"job,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ImportResult previews or reports a vacancy import
+type ImportResult struct {
+	DryRun         bool        `json:"dry_run"`
+	Total          int         `json:"total"`
+	Valid          int         `json:"valid"`
+	Invalid        int         `json:"invalid"`
+	Imported       int         `json:"imported"`
+	IDs            []int64     `json:"ids,omitempty"`
+	IgnoredColumns []string    `json:"ignored_columns,omitempty"`
+	Rows           []ImportRow `json:"rows"`
+}
@@ -0,0 +1,23 @@
+package models
+
+// Match reasons explaining a job/resume score
+const (
+	MatchSpecialty = "specialty"
+	MatchSkills    = "skills"
+	MatchCity      = "city"
+	MatchRelocate  = "relocate"
+	MatchSalary    = "salary"
+	MatchRecent    = "recent"
+)
+
+type JobMatch struct {
+	Job     Job      `json:"job"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+type CandidateMatch struct {
+	Resume  Resume   `json:"resume"`
+	Score   int      `json:"score"`
+	Reasons []string `json:"reasons"`
+}
@@ -3,17 +3,26 @@ package models
 import "time"
 
 type User struct {
-	ID         int64     `json:"id"`
-	TelegramID int64     `json:"telegram_id"`
-	Username   string    `json:"username"`
-	FirstName  string    `json:"first_name"`
-	LastName   string    `json:"last_name"`
-	Phone      string    `json:"phone"`
-	City       string    `json:"city"`
-	Specialty  string    `json:"specialty"`
-	Experience string    `json:"experience"`
-	Role       string    `json:"role"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID             int64     `json:"id"`
+	TelegramID     int64     `json:"telegram_id"`
+	Username       string    `json:"username"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	Phone          string    `json:"phone"`
+	PhoneVerified  bool      `json:"phone_verified"`
+	CompanyID      *int64    `json:"company_id"`
+	City           string    `json:"city"`
+	Specialty      string    `json:"specialty"`
+	Experience     string    `json:"experience"`
+	Role           string    `json:"role"`
+	PipelineStatus string    `json:"pipeline_status"`
+	Tags           []string  `json:"tags"`
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Privacy settings of the user's resume, whose form data is copied here
+	ResumeVisibility string `json:"-"`
+	ResumeAnonymous  bool   `json:"-"`
+	ContactHidden    bool   `json:"contact_hidden"`
 }
 
 type AdminUser struct {
@@ -26,32 +35,94 @@ type AdminUser struct {
 }
 
 type Job struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	Subcategory string    `json:"subcategory"`
-	City        string    `json:"city"`
-	Salary      string    `json:"salary"`
-	Phone       string    `json:"phone"`
-	Company     string    `json:"company"`
-	IsActive    bool      `json:"is_active"`
-	CreatedBy   int64     `json:"created_by"`
-	Source      string    `json:"source"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID              int64      `json:"id"`
+	Title           string     `json:"title"`
+	Description     string     `json:"description"`
+	Category        string     `json:"category"`
+	Subcategory     string     `json:"subcategory"`
+	City            string     `json:"city"`
+	Salary          string     `json:"salary"`
+	Phone           string     `json:"phone"`
+	Company         string     `json:"company"`
+	CompanyID       *int64     `json:"company_id"`
+	CompanyVerified bool       `json:"company_verified"`
+	EmployerRating  float64    `json:"employer_rating"`
+	EmployerReviews int        `json:"employer_reviews"`
+	IsActive        bool       `json:"is_active"`
+	CreatedBy       int64      `json:"created_by"`
+	Source          string     `json:"source"`
+	PhotoFileID     string     `json:"photo_file_id,omitempty"`
+	RiskScore       int        `json:"risk_score"`
+	RiskFlags       []string   `json:"risk_flags"`
+	Latitude        *float64   `json:"latitude"`
+	Longitude       *float64   `json:"longitude"`
+	DistanceKm      float64    `json:"distance_km,omitempty"`
+	Link            string     `json:"link,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"`
+	BumpedAt        *time.Time `json:"bumped_at,omitempty"`
+	DeactivatedBy   string     `json:"deactivated_by,omitempty"`
 }
 
-type Resume struct {
+type Application struct {
 	ID         int64     `json:"id"`
+	JobID      int64     `json:"job_id"`
 	TelegramID int64     `json:"telegram_id"`
-	Username   string    `json:"username"`
-	Name       string    `json:"name"`
-	Phone      string    `json:"phone"`
-	City       string    `json:"city"`
-	Specialty  string    `json:"specialty"`
-	Experience string    `json:"experience"`
 	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type ChannelPost struct {
+	JobID     int64  `json:"job_id"`
+	Channel   string `json:"channel"`
+	MessageID int    `json:"message_id"`
+}
+
+// JobFilter narrows job listings in the CRM API
+type JobFilter struct {
+	MinRisk      int
+	ActiveOnly   bool
+	CreatedSince time.Time
+	Deleted      bool
+}
+
+type BlocklistEntry struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Resume struct {
+	ID              int64            `json:"id"`
+	TelegramID      int64            `json:"telegram_id"`
+	Username        string           `json:"username"`
+	Name            string           `json:"name"`
+	Phone           string           `json:"phone"`
+	City            string           `json:"city"`
+	Specialty       string           `json:"specialty"`
+	Experience      string           `json:"experience"`
+	WorkHistory     []WorkExperience `json:"work_history"`
+	Education       string           `json:"education"`
+	Skills          []string         `json:"skills"`
+	DesiredSalary   string           `json:"desired_salary"`
+	ReadyToRelocate bool             `json:"ready_to_relocate"`
+	Schedule        string           `json:"schedule"`
+	PhotoFileID     string           `json:"photo_file_id,omitempty"`
+	CVFileID        string           `json:"cv_file_id,omitempty"`
+	Visibility      string           `json:"visibility"`
+	Anonymous       bool             `json:"anonymous"`
+	ContactHidden   bool             `json:"contact_hidden"`
+	NotifyMatches   bool             `json:"notify_matches"`
+	PipelineStatus  string           `json:"pipeline_status"`
+	Tags            []string         `json:"tags"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+type WorkExperience struct {
+	Employer string `json:"employer"`
+	Position string `json:"position"`
+	Period   string `json:"period"`
 }
 
 type UserState struct {
@@ -60,6 +131,7 @@ type UserState struct {
 	Subcategory string
 	City        string
 	SearchType  string
+	Location    *GeoPoint
 	TempJob     *Job
 	// Form data
 	FormName       string
@@ -67,6 +139,20 @@ type UserState struct {
 	FormCity       string
 	FormSpecialty  string
 	FormExperience string
+	// Structured resume
+	FormWorkHistory   []WorkExperience
+	FormEducation     string
+	FormSkills        []string
+	FormDesiredSalary string
+	FormRelocate      bool
+	FormSchedule      string
+	FormPhotoFileID   string
+	FormCVFileID      string
+	// Employer review being written
+	ReviewJobID  int64
+	ReviewRating int
+	// PhoneVerified is true when the last phone answer was the user's own shared contact
+	PhoneVerified bool
 	// Message IDs for deletion (collect all, delete at end)
 	FormMessageIDs []int
 }
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Resume visibility levels
+const (
+	VisibilityPublic    = "public"
+	VisibilityOnRequest = "on_request"
+	VisibilityHidden    = "hidden"
+)
+
+var VisibilityLabels = map[string]string{
+	VisibilityPublic:    "🌐 Видна всем",
+	VisibilityOnRequest: "🔐 Контакты по запросу",
+	VisibilityHidden:    "🙈 Скрыта",
+}
+
+type ContactRequest struct {
+	ID          int64      `json:"id"`
+	ResumeID    int64      `json:"resume_id"`
+	AdminID     int64      `json:"admin_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+}
+
+type ContactView struct {
+	ID         int64     `json:"id"`
+	ResumeID   int64     `json:"resume_id"`
+	AdminID    int64     `json:"admin_id"`
+	AdminEmail string    `json:"admin_email"`
+	IPAddress  string    `json:"ip_address"`
+	ViewedAt   time.Time `json:"viewed_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import "time"
+
+// PublicJob is the part of a job exposed by the public API: no author,
+// moderation or internal fields
+type PublicJob struct {
+	ID              int64     `json:"id"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Category        string    `json:"category"`
+	Subcategory     string    `json:"subcategory"`
+	City            string    `json:"city"`
+	Salary          string    `json:"salary"`
+	Phone           string    `json:"phone"`
+	Company         string    `json:"company"`
+	CompanyID       *int64    `json:"company_id"`
+	CompanyVerified bool      `json:"company_verified"`
+	EmployerRating  float64   `json:"employer_rating"`
+	EmployerReviews int       `json:"employer_reviews"`
+	Latitude        *float64  `json:"latitude"`
+	Longitude       *float64  `json:"longitude"`
+	Link            string    `json:"link"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func NewPublicJob(job Job) PublicJob {
+	return PublicJob{
+		ID:              job.ID,
+		Title:           job.Title,
+		Description:     job.Description,
+		Category:        job.Category,
+		Subcategory:     job.Subcategory,
+		City:            job.City,
+		Salary:          job.Salary,
+		Phone:           job.Phone,
+		Company:         job.Company,
+		CompanyID:       job.CompanyID,
+		CompanyVerified: job.CompanyVerified,
+		EmployerRating:  job.EmployerRating,
+		EmployerReviews: job.EmployerReviews,
+		Latitude:        job.Latitude,
+		Longitude:       job.Longitude,
+		Link:            job.Link,
+		CreatedAt:       job.CreatedAt,
+	}
+}
+
+// PublicJobFilter narrows the public job listing
+type PublicJobFilter struct {
+	Category     string
+	Subcategory  string
+	City         string
+	CompanyID    int64
+	Query        string
+	CreatedSince time.Time
+	Limit        int
+	Offset       int
+}
+
+// PublicJobPage is one page of the public job listing
+type PublicJobPage struct {
+	Items  []PublicJob `json:"items"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+type PublicCategory struct {
+	Name          string   `json:"name"`
+	Emoji         string   `json:"emoji"`
+	Subcategories []string `json:"subcategories"`
+}
+
+type PublicCity struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
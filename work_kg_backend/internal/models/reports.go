@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ReportReasons maps report reason codes to the labels shown in the bot
+var ReportReasons = map[string]string{
+	"scam":   "Мошенничество",
+	"prepay": "Требуют предоплату",
+	"fake":   "Недостоверная информация",
+	"spam":   "Спам или дубликат",
+	"other":  "Другое",
+}
+
+// ReportReasonOrder is the order reasons are offered in
+var ReportReasonOrder = []string{"scam", "prepay", "fake", "spam", "other"}
+
+// DeactivatedByReports marks a job hidden automatically after collecting
+// too many reports
+const DeactivatedByReports = "reports"
+
+type JobReport struct {
+	ID         int64     `json:"id"`
+	JobID      int64     `json:"job_id"`
+	TelegramID int64     `json:"telegram_id"`
+	Reason     string    `json:"reason"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReportedJob struct {
+	Job
+	PendingReports int            `json:"pending_reports"`
+	TotalReports   int            `json:"total_reports"`
+	Reasons        map[string]int `json:"reasons"`
+	LastReportedAt time.Time      `json:"last_reported_at"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Review moderation statuses
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// EmployerReview is a job seeker's rating of the employer behind a job
+// they applied to. The employer is the job's company when it has one,
+// otherwise the bot user who posted the job.
+type EmployerReview struct {
+	ID                 int64      `json:"id"`
+	JobID              int64      `json:"job_id"`
+	JobTitle           string     `json:"job_title,omitempty"`
+	CompanyID          *int64     `json:"company_id"`
+	EmployerTelegramID int64      `json:"employer_telegram_id"`
+	TelegramID         int64      `json:"telegram_id"`
+	Rating             int        `json:"rating"`
+	Comment            string     `json:"comment"`
+	Status             string     `json:"status"`
+	CreatedAt          time.Time  `json:"created_at"`
+	ModeratedAt        *time.Time `json:"moderated_at"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Time series metrics
+const (
+	MetricJobs         = "jobs"
+	MetricUsers        = "users"
+	MetricResumes      = "resumes"
+	MetricApplications = "applications"
+)
+
+// Time series buckets, named after PostgreSQL's date_trunc fields
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Time series breakdowns
+const (
+	GroupByCategory = "category"
+	GroupByCity     = "city"
+	GroupBySource   = "source"
+)
+
+// TimeSeriesFilter selects the range, bucket size and breakdown of a time series
+type TimeSeriesFilter struct {
+	From     time.Time
+	To       time.Time
+	Interval string
+	GroupBy  string
+}
+
+// TimeSeriesCount is the number of new records of one metric and group
+// created in one bucket
+type TimeSeriesCount struct {
+	Metric string
+	Group  string
+	Bucket time.Time
+	Count  int
+}
+
+// TimeSeries holds zero-filled counts per bucket for each metric and group.
+// Group is empty when there's no breakdown or the metric has no such field.
+type TimeSeries struct {
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	Interval string           `json:"interval"`
+	GroupBy  string           `json:"group_by,omitempty"`
+	Buckets  []time.Time      `json:"buckets"`
+	Series   []TimeSeriesLine `json:"series"`
+	Totals   map[string]int   `json:"totals"`
+}
+
+type TimeSeriesLine struct {
+	Metric string `json:"metric"`
+	Group  string `json:"group"`
+	Counts []int  `json:"counts"`
+	Total  int    `json:"total"`
+}
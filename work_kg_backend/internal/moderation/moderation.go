@@ -0,0 +1,187 @@
+package moderation
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"work_kg_backend/internal/config"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+// Risk flags stored on jobs
+const (
+	FlagBlockedPhone   = "blocked_phone"
+	FlagBlockedKeyword = "blocked_keyword"
+	FlagDuplicate      = "duplicate"
+	FlagMultiAccount   = "multi_account"
+)
+
+const (
+	// duplicateWindow is how far back new jobs are compared for duplicates
+	duplicateWindow = 30 * 24 * time.Hour
+	// Word-set similarity above which two jobs count as duplicates; lower
+	// when both jobs share the contact phone
+	duplicateSimilarity          = 0.85
+	duplicateSimilaritySamePhone = 0.7
+)
+
+var ErrRateLimited = errors.New("Слишком много вакансий за сутки. Попробуйте завтра")
+
+var (
+	maxJobsPerDay = 5
+	riskThreshold = 70
+)
+
+func Configure(cfg *config.Config) {
+	maxJobsPerDay = cfg.MaxJobsPerDay
+	riskThreshold = cfg.RiskThreshold
+}
+
+// Checker runs the spam pipeline on many jobs with the blocklist loaded
+// once, for batches such as imports. Jobs of the batch are also compared
+// with each other, since they aren't saved yet.
+type Checker struct {
+	blocklist []models.BlocklistEntry
+	checked   []models.Job
+}
+
+// NewChecker loads the current blocklist
+func NewChecker() *Checker {
+	blocklist, err := database.GetBlocklist()
+	if err != nil {
+		log.Printf("Error loading blocklist: %v", err)
+	}
+	return &Checker{blocklist: blocklist}
+}
+
+// Check runs the spam pipeline on a new job before it is saved. It rejects
+// authors over their daily limit with ErrRateLimited, otherwise stores the
+// risk score and flags on the job and deactivates it for moderation when
+// the score reaches the threshold.
+func Check(job *models.Job) error {
+	return NewChecker().Check(job)
+}
+
+// Check works like the package-level Check
+func (c *Checker) Check(job *models.Job) error {
+	if job.CreatedBy != 0 && maxJobsPerDay > 0 {
+		if database.CountJobsByCreatorSince(job.CreatedBy, time.Now().Add(-24*time.Hour)) >= maxJobsPerDay {
+			return ErrRateLimited
+		}
+	}
+
+	score := 0
+	var flags []string
+
+	text := normalizeText(job.Title + " " + job.Description)
+	phone := phoneDigits(job.Phone)
+	for _, entry := range c.blocklist {
+		switch entry.Kind {
+		case "phone":
+			if phone != "" && phone == phoneDigits(entry.Value) && !hasFlag(flags, FlagBlockedPhone) {
+				score += 100
+				flags = append(flags, FlagBlockedPhone)
+			}
+		case "keyword":
+			keyword := normalizeText(entry.Value)
+			if keyword != "" && strings.Contains(" "+text+" ", " "+keyword+" ") && !hasFlag(flags, FlagBlockedKeyword) {
+				score += 100
+				flags = append(flags, FlagBlockedKeyword)
+			}
+		}
+	}
+
+	candidates, err := database.GetDuplicateCandidates(job, time.Now().Add(-duplicateWindow))
+	if err != nil {
+		log.Printf("Error loading duplicate candidates: %v", err)
+	}
+	candidates = append(candidates, c.checked...)
+	c.checked = append(c.checked, *job)
+	words := wordSet(text)
+	for _, candidate := range candidates {
+		threshold := duplicateSimilarity
+		if phone != "" && phone == phoneDigits(candidate.Phone) {
+			threshold = duplicateSimilaritySamePhone
+		}
+		if similarity(words, wordSet(normalizeText(candidate.Title+" "+candidate.Description))) < threshold {
+			continue
+		}
+
+		if !hasFlag(flags, FlagDuplicate) {
+			score += 60
+			flags = append(flags, FlagDuplicate)
+		}
+		if candidate.CreatedBy != job.CreatedBy && !hasFlag(flags, FlagMultiAccount) {
+			score += 20
+			flags = append(flags, FlagMultiAccount)
+		}
+	}
+
+	if score > 100 {
+		score = 100
+	}
+	job.RiskScore = score
+	job.RiskFlags = flags
+	if riskThreshold > 0 && score >= riskThreshold {
+		job.IsActive = false
+	}
+
+	return nil
+}
+
+// normalizeText lowercases the text and reduces it to words separated by single spaces
+func normalizeText(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+func phoneDigits(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	// Compare by the 9-digit local number so +996/0 prefixes don't matter
+	if len(digits) > 9 {
+		digits = digits[len(digits)-9:]
+	}
+	return digits
+}
+
+func wordSet(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(text) {
+		set[word] = true
+	}
+	return set
+}
+
+// similarity is the Jaccard index of two word sets
+func similarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+func hasFlag(flags []string, flag string) bool {
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package retention
+
+import (
+	"log"
+	"time"
+
+	"work_kg_backend/internal/config"
+	"work_kg_backend/internal/database"
+)
+
+const purgeInterval = 24 * time.Hour
+
+// Start permanently removes jobs that have been in the trash longer than the
+// configured retention period. It blocks, so run it in a goroutine.
+func Start(cfg *config.Config) {
+	if cfg.JobRetentionDays <= 0 {
+		log.Println("Job retention disabled, deleted jobs are kept in the trash")
+		return
+	}
+	retention := time.Duration(cfg.JobRetentionDays) * 24 * time.Hour
+
+	purgeJobs(retention)
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purgeJobs(retention)
+	}
+}
+
+func purgeJobs(retention time.Duration) {
+	purged, err := database.PurgeDeletedJobs(time.Now().Add(-retention))
+	if err != nil {
+		return
+	}
+	if purged > 0 {
+		log.Printf("Purged %d jobs deleted more than %s ago", purged, retention)
+	}
+}
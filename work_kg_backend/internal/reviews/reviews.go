@@ -0,0 +1,91 @@
+package reviews
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"work_kg_backend/internal/config"
+	"work_kg_backend/internal/database"
+	"work_kg_backend/internal/models"
+)
+
+const (
+	// maxReviewsPerDay limits how many employers one user can rate per day
+	maxReviewsPerDay = 3
+	// reviewSpikeLimit is how many reviews an employer may get in a day
+	// before new ones are held for moderation
+	reviewSpikeLimit = 5
+)
+
+var (
+	ErrNoEmployer      = errors.New("У этой вакансии нет работодателя, которого можно оценить")
+	ErrNotApplied      = errors.New("Оценить работодателя можно только после отклика на его вакансию")
+	ErrTooEarly        = errors.New("Оставить отзыв можно через некоторое время после отклика")
+	ErrSelfReview      = errors.New("Нельзя оценивать собственную компанию")
+	ErrAlreadyReviewed = errors.New("Вы уже оценили этого работодателя")
+	ErrRateLimited     = errors.New("Слишком много отзывов за сутки. Попробуйте завтра")
+)
+
+// cooldown is how long after applying a seeker has to wait before rating
+var cooldown = 72 * time.Hour
+
+func Configure(cfg *config.Config) {
+	cooldown = time.Duration(cfg.ReviewCooldownHours) * time.Hour
+}
+
+// Cooldown is the wait between applying to a job and reviewing its employer
+func Cooldown() time.Duration {
+	return cooldown
+}
+
+// Submit checks that the reviewer may rate the job's employer, then stores
+// the review. Reviews with text, or arriving while the employer gets an
+// unusual number of them, wait for moderation; the rest are published.
+func Submit(review *models.EmployerReview, job *models.Job) error {
+	switch {
+	case job.CompanyID != nil:
+		review.CompanyID = job.CompanyID
+	case job.CreatedBy != 0:
+		review.EmployerTelegramID = job.CreatedBy
+	default:
+		return ErrNoEmployer
+	}
+	review.JobID = job.ID
+
+	if review.TelegramID == job.CreatedBy {
+		return ErrSelfReview
+	}
+	if job.CompanyID != nil {
+		company, err := database.GetCompanyByTelegramID(review.TelegramID)
+		if err == nil && company.ID == *job.CompanyID {
+			return ErrSelfReview
+		}
+	}
+
+	application, err := database.GetApplication(job.ID, review.TelegramID)
+	if err == sql.ErrNoRows {
+		return ErrNotApplied
+	}
+	if err != nil {
+		return err
+	}
+	if time.Since(application.CreatedAt) < cooldown {
+		return ErrTooEarly
+	}
+
+	if database.HasReviewedEmployer(review.TelegramID, review.CompanyID, review.EmployerTelegramID) {
+		return ErrAlreadyReviewed
+	}
+	dayAgo := time.Now().Add(-24 * time.Hour)
+	if database.CountReviewsByUserSince(review.TelegramID, dayAgo) >= maxReviewsPerDay {
+		return ErrRateLimited
+	}
+
+	review.Status = models.ReviewApproved
+	if review.Comment != "" || database.CountEmployerReviewsSince(review.CompanyID, review.EmployerTelegramID, dayAgo) >= reviewSpikeLimit {
+		review.Status = models.ReviewPending
+	}
+
+	return database.SaveEmployerReview(review)
+}
@@ -0,0 +1,322 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// FormatFromName picks the format from a file name's extension
+func FormatFromName(name string) (string, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return CSV, nil
+	case ".xlsx":
+		return XLSX, nil
+	}
+	return "", fmt.Errorf("unsupported file type %q, expected .csv or .xlsx", path.Ext(name))
+}
+
+// maxEntrySize caps the uncompressed size of each part of an xlsx file, so
+// a small upload can't expand into gigabytes
+const maxEntrySize = 64 << 20
+
+var (
+	// ErrTooManyRows is returned as soon as a file has more rows than allowed
+	ErrTooManyRows   = errors.New("file has too many rows")
+	errEntryTooLarge = errors.New("xlsx file is too large once unpacked")
+)
+
+// ReadAll reads the rows of the first sheet, failing with ErrTooManyRows
+// once there are more than maxRows (0 for no limit). Rows are returned as
+// written, so they may differ in length.
+func ReadAll(r io.Reader, format string, maxRows int) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case CSV:
+		return readCSV(data, maxRows)
+	case XLSX:
+		return readXLSX(data, maxRows)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func readCSV(data []byte, maxRows int) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	// Excel with a Russian locale saves CSV with semicolons
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	var rows [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if maxRows > 0 && len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		rows = append(rows, record)
+	}
+}
+
+// maxColumns is the number of columns in an Excel sheet, A to XFD
+const maxColumns = 16384
+
+var errTooManyColumns = errors.New("xlsx file has cells past column XFD")
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelationshipID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+// xlsxText is a plain or rich text string; rich text is split into runs
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var text strings.Builder
+	for _, run := range t.Runs {
+		text.WriteString(run.T)
+	}
+	return text.String()
+}
+
+type xlsxRow struct {
+	Cells []struct {
+		Ref    string   `xml:"r,attr"`
+		Type   string   `xml:"t,attr"`
+		Value  string   `xml:"v"`
+		Inline xlsxText `xml:"is"`
+	} `xml:"c"`
+}
+
+func readXLSX(data []byte, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("not a valid xlsx file")
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := openEntry(sheetFile)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Rows are decoded one at a time so the row limit applies while reading
+	var rows [][]string
+	decoder := xml.NewDecoder(reader)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", sheetFile.Name, err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		if maxRows > 0 && len(rows) == maxRows {
+			return nil, ErrTooManyRows
+		}
+		var row xlsxRow
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", sheetFile.Name, err)
+		}
+		values, err := rowValues(row, shared)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, values)
+	}
+}
+
+func rowValues(row xlsxRow, shared xlsxSharedStrings) ([]string, error) {
+	var values []string
+	for i, cell := range row.Cells {
+		column, err := columnIndex(cell.Ref)
+		if err != nil {
+			return nil, err
+		}
+		if column < 0 {
+			column = i
+		}
+		if column >= maxColumns {
+			return nil, errTooManyColumns
+		}
+		for len(values) <= column {
+			values = append(values, "")
+		}
+
+		switch cell.Type {
+		case "s":
+			index, err := strconv.Atoi(cell.Value)
+			if err == nil && index >= 0 && index < len(shared.Items) {
+				values[column] = shared.Items[index].String()
+			}
+		case "inlineStr":
+			values[column] = cell.Inline.String()
+		case "", "n":
+			values[column] = formatNumber(cell.Value)
+		default:
+			values[column] = cell.Value
+		}
+	}
+	return values, nil
+}
+
+// firstSheet finds the worksheet listed first in the workbook
+func firstSheet(files map[string]*zip.File) (*zip.File, error) {
+	var workbook xlsxWorkbook
+	var relationships xlsxRelationships
+	workbookFile, ok := files["xl/workbook.xml"]
+	relsFile, relsOK := files["xl/_rels/workbook.xml.rels"]
+	if ok && relsOK && decodeXML(workbookFile, &workbook) == nil && decodeXML(relsFile, &relationships) == nil && len(workbook.Sheets) > 0 {
+		for _, rel := range relationships.Relationships {
+			if rel.ID != workbook.Sheets[0].RelationshipID {
+				continue
+			}
+			name := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(name, "xl/") {
+				name = "xl/" + name
+			}
+			if f, ok := files[name]; ok {
+				return f, nil
+			}
+		}
+	}
+
+	if f, ok := files["xl/worksheets/sheet1.xml"]; ok {
+		return f, nil
+	}
+	return nil, errors.New("xlsx file has no worksheets")
+}
+
+// openEntry opens a part of the xlsx file, refusing to unpack more than
+// maxEntrySize bytes whatever size the archive claims
+func openEntry(f *zip.File) (io.ReadCloser, error) {
+	if f.UncompressedSize64 > maxEntrySize {
+		return nil, errEntryTooLarge
+	}
+	reader, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &limitedEntry{ReadCloser: reader, remaining: maxEntrySize}, nil
+}
+
+// limitedEntry fails reads past the size limit instead of ending quietly,
+// so a truncated part is never mistaken for a complete one
+type limitedEntry struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (l *limitedEntry) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, errEntryTooLarge
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+func decodeXML(f *zip.File, v interface{}) error {
+	reader, err := openEntry(f)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := xml.NewDecoder(reader).Decode(v); err != nil {
+		return fmt.Errorf("invalid %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex turns a cell reference like "AB12" into a zero-based column,
+// or -1 when the reference is missing. Columns past XFD, the last one
+// Excel supports, are rejected before they can overflow.
+func columnIndex(ref string) (int, error) {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+		if column > maxColumns {
+			return 0, errTooManyColumns
+		}
+	}
+	return column - 1, nil
+}
+
+// formatNumber undoes the exponent notation Excel uses for long numbers,
+// such as phone numbers typed without a leading plus
+func formatNumber(value string) string {
+	if !strings.ContainsAny(value, "eE") {
+		return value
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(number, 'f', -1, 64)
+}
@@ -0,0 +1,182 @@
+// Package spreadsheet reads and writes the CSV and XLSX files the CRM
+// exchanges with the team and partners. XLSX support covers a single sheet
+// of plain text cells, which is all exports and imports need.
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Supported formats
+const (
+	CSV  = "csv"
+	XLSX = "xlsx"
+)
+
+// ContentTypes maps each format to its MIME type
+var ContentTypes = map[string]string{
+	CSV:  "text/csv; charset=utf-8",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// Writer writes rows one at a time so large tables never sit in memory
+type Writer interface {
+	WriteRow(values []string) error
+	// Close finishes the file. It does not close the underlying writer.
+	Close() error
+}
+
+// NewWriter starts a file in the given format
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w)
+	case XLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// escapeFormula prefixes values a spreadsheet would run as a formula with a
+// quote, so text typed by bot users can't execute when a CSV file is opened.
+// Phones and amounts like "+996 555 123 456" or "-500" can't call anything
+// and are left as they are.
+func escapeFormula(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if (value[0] == '+' || value[0] == '-') && isNumeric(value[1:]) {
+		return value
+	}
+	return "'" + value
+}
+
+// isNumeric reports whether value holds digits and number punctuation only
+func isNumeric(value string) bool {
+	digits := 0
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case !strings.ContainsRune(" .,-()", r):
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// csvFlushRows is how often buffered CSV rows are pushed to the client
+const csvFlushRows = 100
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	// The byte order mark makes Excel read Cyrillic text as UTF-8
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+func (c *csvWriter) WriteRow(values []string) error {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeFormula(value)
+	}
+	if err := c.w.Write(escaped); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%csvFlushRows == 0 {
+		c.w.Flush()
+	}
+	return c.w.Error()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// The fixed parts of a one-sheet workbook
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet io.Writer
+	rows  int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+	for _, part := range xlsxParts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	// The sheet is the last entry, so rows can be streamed into it
+	sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &xlsxWriter{zip: archive, sheet: sheet}, nil
+}
+
+func (x *xlsxWriter) WriteRow(values []string) error {
+	x.rows++
+	var row strings.Builder
+	fmt.Fprintf(&row, `<row r="%d">`, x.rows)
+	// Inline strings are never evaluated, so unlike CSV they need no escaping
+	for _, value := range values {
+		row.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(&row, []byte(value))
+		row.WriteString(`</t></is></c>`)
+	}
+	row.WriteString(`</row>`)
+	_, err := io.WriteString(x.sheet, row.String())
+	return err
+}
+
+func (x *xlsxWriter) Close() error {
+	if _, err := io.WriteString(x.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return x.zip.Close()
+}
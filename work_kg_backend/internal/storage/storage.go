@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"work_kg_backend/internal/config"
+)
+
+// Storage keeps copies of files users sent to the bot, keyed by Telegram
+// file_id. An S3-compatible backend only needs to implement this interface.
+type Storage interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+}
+
+var ErrNotFound = errors.New("file not found")
+
+// Files is the configured storage, or nil when files are only kept on Telegram
+var Files Storage
+
+func Configure(cfg *config.Config) {
+	if cfg.StorageDir == "" {
+		return
+	}
+	if err := os.MkdirAll(cfg.StorageDir, 0o755); err != nil {
+		log.Printf("Failed to create storage directory: %v", err)
+		return
+	}
+	Files = &LocalStorage{Dir: cfg.StorageDir}
+}
+
+// LocalStorage stores files in a directory on disk
+type LocalStorage struct {
+	Dir string
+}
+
+// Telegram file IDs are URL-safe base64, anything else is rejected
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if !validKey.MatchString(key) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(s.Dir, key), nil
+}
+
+func (s *LocalStorage) Save(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, "upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Open(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
@@ -0,0 +1,149 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"work_kg_backend/internal/models"
+)
+
+// Field length limits, matching the column sizes in the database schema
+const (
+	MaxNameLength        = 100
+	MaxTitleLength       = 255
+	MaxDescriptionLength = 2000
+	MaxSalaryLength      = 100
+	MaxCompanyLength     = 255
+	MaxCityLength        = 100
+	MaxSpecialtyLength   = 255
+	MaxExperienceLength  = 1000
+	MaxReviewLength      = 500
+	MaxNoteLength        = 2000
+	MaxTagLength         = 50
+)
+
+var ErrEmpty = errors.New("Пожалуйста, отправьте ответ текстом")
+
+var (
+	phoneJunk    = regexp.MustCompile(`[\s\-()]`)
+	salaryNumber = regexp.MustCompile(`\d[\d\s]*`)
+	// Salaries without a number that are still meaningful
+	salaryWords = []string{"договор", "сдельн", "оклад"}
+)
+
+// Text trims the input and checks it is non-empty and within limits.
+func Text(text string, minLen, maxLen int) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+
+	length := utf8.RuneCountInString(text)
+	if length < minLen {
+		return "", fmt.Errorf("Слишком коротко: минимум %d символа", minLen)
+	}
+	if length > maxLen {
+		return "", fmt.Errorf("Слишком длинно: максимум %d символов, у вас %d", maxLen, length)
+	}
+	return text, nil
+}
+
+// Phone accepts a Kyrgyz mobile number in any common spelling
+// (+996555123456, 996 555 123 456, 0555 123 456, 555-12-34-56)
+// and normalizes it to "+996 XXX XXX XXX".
+func Phone(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", ErrEmpty
+	}
+
+	digits := phoneJunk.ReplaceAllString(text, "")
+	digits = strings.TrimPrefix(digits, "+")
+
+	switch {
+	case len(digits) == 12 && strings.HasPrefix(digits, "996"):
+		digits = digits[3:]
+	case len(digits) == 10 && strings.HasPrefix(digits, "0"):
+		digits = digits[1:]
+	}
+
+	if len(digits) != 9 {
+		return "", errors.New("Неверный формат номера. Пример: +996 555 123 456")
+	}
+	if _, err := strconv.ParseUint(digits, 10, 64); err != nil {
+		return "", errors.New("Номер должен содержать только цифры. Пример: +996 555 123 456")
+	}
+
+	return fmt.Sprintf("+996 %s %s %s", digits[0:3], digits[3:6], digits[6:9]), nil
+}
+
+// Salary accepts an amount or range ("30000-50000 сом", "от 1500 сом/день")
+// or a known wording like "договорная".
+func Salary(text string) (string, error) {
+	text, err := Text(text, 1, MaxSalaryLength)
+	if err != nil {
+		return "", err
+	}
+
+	numbers := salaryNumber.FindAllString(text, -1)
+	if len(numbers) == 0 {
+		lower := strings.ToLower(text)
+		for _, word := range salaryWords {
+			if strings.Contains(lower, word) {
+				return text, nil
+			}
+		}
+		return "", errors.New("Укажите сумму, например: 30000-50000 сом или «договорная»")
+	}
+
+	amounts := make([]int, len(numbers))
+	for i, number := range numbers {
+		amount, err := strconv.Atoi(strings.Join(strings.Fields(number), ""))
+		if err != nil {
+			return "", errors.New("Сумма зарплаты слишком большая")
+		}
+		amounts[i] = amount
+	}
+
+	if len(amounts) >= 2 && strings.Contains(text, "-") && amounts[0] > amounts[1] {
+		return "", errors.New("Минимальная зарплата больше максимальной")
+	}
+
+	return text, nil
+}
+
+// Job validates and normalizes a vacancy submitted through the API.
+func Job(job *models.Job) error {
+	var err error
+
+	if job.Title, err = Text(job.Title, 3, MaxTitleLength); err != nil {
+		return fmt.Errorf("title: %w", err)
+	}
+	if job.Description != "" {
+		if job.Description, err = Text(job.Description, 1, MaxDescriptionLength); err != nil {
+			return fmt.Errorf("description: %w", err)
+		}
+	}
+	if job.Salary != "" {
+		if job.Salary, err = Salary(job.Salary); err != nil {
+			return fmt.Errorf("salary: %w", err)
+		}
+	}
+	if job.Phone != "" {
+		if job.Phone, err = Phone(job.Phone); err != nil {
+			return fmt.Errorf("phone: %w", err)
+		}
+	}
+	if utf8.RuneCountInString(job.Company) > MaxCompanyLength {
+		return fmt.Errorf("company: максимум %d символов", MaxCompanyLength)
+	}
+	if utf8.RuneCountInString(job.City) > MaxCityLength {
+		return fmt.Errorf("city: максимум %d символов", MaxCityLength)
+	}
+
+	return nil
+}
@@ -7,6 +7,10 @@ import (
 	"work_kg_backend/internal/config"
 	"work_kg_backend/internal/database"
 	"work_kg_backend/internal/handlers"
+	"work_kg_backend/internal/moderation"
+	"work_kg_backend/internal/retention"
+	"work_kg_backend/internal/reviews"
+	"work_kg_backend/internal/storage"
 )
 
 func main() {
@@ -22,9 +26,21 @@ func main() {
 	// Initialize database schema
 	database.InitSchema()
 
+	// Configure spam and duplicate detection
+	moderation.Configure(cfg)
+
+	// Configure employer review rules
+	reviews.Configure(cfg)
+
+	// Configure storage for files sent to the bot
+	storage.Configure(cfg)
+
 	// Start Telegram bot in goroutine
-	go bot.Start(cfg.TelegramToken)
+	go bot.Start(cfg)
+
+	// Purge jobs that have been in the trash past the retention period
+	go retention.Start(cfg)
 
 	// Start HTTP server (blocking)
-	handlers.StartServer(cfg.ServerPort)
+	handlers.StartServer(cfg)
 }